@@ -0,0 +1,294 @@
+package backup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultKeep   = 20
+	DefaultMaxAge = 30 * 24 * time.Hour
+
+	journalName = "journal.json"
+)
+
+var ErrNoBackups = errors.New("no backups found")
+
+// Store keeps a copy of every PDF file before it's overwritten.
+type Store struct {
+	dir    string
+	keep   int
+	maxAge time.Duration
+}
+
+type File struct {
+	Original string      `json:"original"`
+	Backup   string      `json:"backup,omitempty"`
+	Mode     fs.FileMode `json:"mode,omitempty"`
+	Created  bool        `json:"created,omitempty"`
+}
+
+type Operation struct {
+	ID    string    `json:"id"`
+	Op    string    `json:"op"`
+	Time  time.Time `json:"time"`
+	Files []File    `json:"files"`
+
+	store *Store
+	mu    sync.Mutex
+}
+
+func DefaultDir() (string, error) {
+	dataDir := os.Getenv("XDG_DATA_HOME")
+	if dataDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataDir = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataDir, "pdfmc", "backups"), nil
+}
+
+// NewStore doesn't limit the backups for a keep or maxAge of 0.
+func NewStore(dir string, keep int, maxAge time.Duration) *Store {
+	return &Store{
+		dir:    dir,
+		keep:   keep,
+		maxAge: maxAge,
+	}
+}
+
+// Begin doesn't write anything until the first file is saved.
+func (s *Store) Begin(op string) *Operation {
+	now := time.Now()
+	return &Operation{
+		ID:    now.Format("20060102-150405.000000"),
+		Op:    op,
+		Time:  now,
+		store: s,
+	}
+}
+
+func (o *Operation) Save(path string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	original, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if err := o.create(); err != nil {
+		return err
+	}
+
+	info, err := os.Stat(original)
+	if err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%03d_%s", len(o.Files)+1, filepath.Base(original))
+	if err := copyFile(original, filepath.Join(o.store.operationDir(o.ID), name), 0600); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", path, err)
+	}
+
+	o.Files = append(o.Files, File{Original: original, Backup: name, Mode: info.Mode().Perm()})
+	return o.writeJournal()
+}
+
+func (o *Operation) Created(path string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	original, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if err := o.create(); err != nil {
+		return err
+	}
+
+	o.Files = append(o.Files, File{Original: original, Created: true})
+	return o.writeJournal()
+}
+
+func (o *Operation) create() error {
+	if len(o.Files) > 0 {
+		return nil
+	}
+	if err := os.MkdirAll(o.store.operationDir(o.ID), 0700); err != nil {
+		return err
+	}
+	// the retention limits are applied once the operation exists, so an
+	// operation that didn't write anything doesn't push others out
+	return o.store.prune(o.ID)
+}
+
+func (o *Operation) writeJournal() error {
+	content, err := json.MarshalIndent(o, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	journal := filepath.Join(o.store.operationDir(o.ID), journalName)
+	tmp := journal + ".tmp"
+	if err := os.WriteFile(tmp, content, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, journal)
+}
+
+func (s *Store) List() ([]*Operation, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var operations []*Operation
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(s.dir, entry.Name(), journalName))
+		if errors.Is(err, fs.ErrNotExist) {
+			// an operation that was interrupted before it saved anything
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		operation := &Operation{store: s}
+		if err := json.Unmarshal(content, operation); err != nil {
+			return nil, fmt.Errorf("failed to read the journal of %s: %w", entry.Name(), err)
+		}
+		operations = append(operations, operation)
+	}
+
+	sort.Slice(operations, func(i, j int) bool {
+		return operations[i].Time.After(operations[j].Time)
+	})
+	return operations, nil
+}
+
+func (s *Store) Last() (*Operation, error) {
+	operations, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(operations) == 0 {
+		return nil, ErrNoBackups
+	}
+	return operations[0], nil
+}
+
+func (s *Store) Get(id string) (*Operation, error) {
+	operations, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, operation := range operations {
+		if operation.ID == id {
+			return operation, nil
+		}
+	}
+	return nil, fmt.Errorf("%w with the id %s", ErrNoBackups, id)
+}
+
+// Restore removes the files the operation created and the operation itself.
+func (s *Store) Restore(o *Operation) ([]File, error) {
+	var restored []File
+
+	// the same file may have been saved more than once, the oldest copy is
+	// the one from before the operation
+	for i := len(o.Files) - 1; i >= 0; i-- {
+		file := o.Files[i]
+		if file.Created {
+			if err := os.Remove(file.Original); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return restored, fmt.Errorf("failed to remove %s: %w", file.Original, err)
+			}
+		} else if err := restoreFile(filepath.Join(s.operationDir(o.ID), file.Backup), file.Original, file.Mode); err != nil {
+			return restored, fmt.Errorf("failed to restore %s: %w", file.Original, err)
+		}
+		restored = append(restored, file)
+	}
+
+	return restored, os.RemoveAll(s.operationDir(o.ID))
+}
+
+func (s *Store) prune(current string) error {
+	operations, err := s.List()
+	if err != nil {
+		return err
+	}
+
+	kept := 1
+	for _, operation := range operations {
+		if operation.ID == current {
+			continue
+		}
+
+		tooOld := s.maxAge > 0 && time.Since(operation.Time) > s.maxAge
+		if !tooOld && (s.keep <= 0 || kept < s.keep) {
+			kept++
+			continue
+		}
+		if err := os.RemoveAll(s.operationDir(operation.ID)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) operationDir(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+// restoreFile replaces the original in a single rename.
+func restoreFile(backup, original string, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(original), 0750); err != nil {
+		return err
+	}
+
+	tmp := filepath.Join(filepath.Dir(original), "."+filepath.Base(original)+".restore.tmp")
+	if err := copyFile(backup, tmp, mode); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, original)
+}
+
+func copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(filepath.Clean(src))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(filepath.Clean(dst), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
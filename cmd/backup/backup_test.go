@@ -0,0 +1,155 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	err := os.WriteFile(path, []byte(content), 0640)
+	assert.NoError(t, err, "failed to write file: ", path)
+}
+
+func TestSaveAndRestore(t *testing.T) {
+	tempDir := t.TempDir()
+	store := NewStore(filepath.Join(tempDir, "backups"), DefaultKeep, DefaultMaxAge)
+	file := filepath.Join(tempDir, "test.pdf")
+
+	writeFile(t, file, "original")
+	operation := store.Begin("encrypt")
+	assert.NoError(t, operation.Save(file))
+
+	// the same file overwritten twice by one operation
+	writeFile(t, file, "merged")
+	assert.NoError(t, operation.Save(file))
+	writeFile(t, file, "encrypted")
+
+	last, err := store.Last()
+	assert.NoError(t, err)
+	assert.Equal(t, operation.ID, last.ID)
+	assert.Equal(t, "encrypt", last.Op)
+	assert.Len(t, last.Files, 2)
+
+	restored, err := store.Restore(last)
+	assert.NoError(t, err)
+	assert.Len(t, restored, 2)
+	for _, f := range restored {
+		assert.Equal(t, file, f.Original)
+	}
+
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	assert.Equal(t, "original", string(content))
+
+	info, err := os.Stat(file)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0640), info.Mode().Perm())
+
+	_, err = store.Last()
+	assert.ErrorIs(t, err, ErrNoBackups)
+}
+
+func TestCreatedAndRestore(t *testing.T) {
+	tempDir := t.TempDir()
+	store := NewStore(filepath.Join(tempDir, "backups"), DefaultKeep, DefaultMaxAge)
+	file := filepath.Join(tempDir, "merged.pdf")
+
+	operation := store.Begin("merge")
+	writeFile(t, file, "merged")
+	assert.NoError(t, operation.Created(file))
+
+	// the created file overwritten by the same operation
+	assert.NoError(t, operation.Save(file))
+	writeFile(t, file, "encrypted")
+
+	last, err := store.Last()
+	assert.NoError(t, err)
+	assert.Equal(t, operation.ID, last.ID)
+
+	restored, err := store.Restore(last)
+	assert.NoError(t, err)
+	assert.Len(t, restored, 2)
+	assert.True(t, restored[1].Created)
+	assert.NoFileExists(t, file, "Expected the created file to be removed")
+}
+
+func TestGet(t *testing.T) {
+	tempDir := t.TempDir()
+	store := NewStore(filepath.Join(tempDir, "backups"), DefaultKeep, DefaultMaxAge)
+	file := filepath.Join(tempDir, "test.pdf")
+	writeFile(t, file, "original")
+
+	operation := store.Begin("decrypt")
+	assert.NoError(t, operation.Save(file))
+
+	found, err := store.Get(operation.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, operation.ID, found.ID)
+
+	_, err = store.Get("missing")
+	assert.ErrorIs(t, err, ErrNoBackups)
+}
+
+func TestEmptyOperation(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "backups"), DefaultKeep, DefaultMaxAge)
+	store.Begin("merge")
+
+	operations, err := store.List()
+	assert.NoError(t, err)
+	assert.Empty(t, operations, "Expected an operation without files to leave nothing behind")
+}
+
+func TestRetention(t *testing.T) {
+	tests := []struct {
+		name     string
+		keep     int
+		maxAge   time.Duration
+		ages     []time.Duration
+		expected int
+	}{
+		{
+			name:     "keep the newest operations",
+			keep:     2,
+			ages:     []time.Duration{3 * time.Hour, 2 * time.Hour, time.Hour},
+			expected: 2,
+		},
+		{
+			name:     "remove old operations",
+			maxAge:   90 * time.Minute,
+			ages:     []time.Duration{3 * time.Hour, 2 * time.Hour, time.Hour},
+			expected: 2,
+		},
+		{
+			name:     "no limits",
+			ages:     []time.Duration{3 * time.Hour, 2 * time.Hour, time.Hour},
+			expected: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			store := NewStore(filepath.Join(tempDir, "backups"), tt.keep, tt.maxAge)
+			file := filepath.Join(tempDir, "test.pdf")
+			writeFile(t, file, "original")
+
+			for _, age := range tt.ages {
+				operation := store.Begin("encrypt")
+				operation.ID = time.Now().Add(-age).Format("20060102-150405.000000")
+				operation.Time = time.Now().Add(-age)
+				assert.NoError(t, operation.Save(file))
+			}
+
+			// the retention limits are applied when the next operation saves a file
+			assert.NoError(t, store.Begin("encrypt").Save(file))
+
+			operations, err := store.List()
+			assert.NoError(t, err)
+			assert.Len(t, operations, tt.expected)
+		})
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright © 2025 Aito Nakajima
+*/
+package cmd
+
+import (
+	"runtime"
+
+	"github.com/gmskazi/pdfmc/cmd/autocomplete"
+	"github.com/gmskazi/pdfmc/cmd/program"
+	"github.com/spf13/cobra"
+)
+
+// changePasswordCmd represents the change-password command
+var changePasswordCmd = &cobra.Command{
+	Use:     "change-password [files... or folder]",
+	Aliases: []string{"rekey"},
+	Short:   "Change the password of encrypted PDF files.",
+	Long: `This is a tool to change the password of encrypted PDF files in a single step,
+the PDF files are never written to disk unencrypted.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		p := program.NewProgram(cmd, args, changePassword)
+		return p.ExecuteChangePassword()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(changePasswordCmd)
+
+	changePasswordCmd.Flags().String("old-password", "", "Current password of the PDF files.")
+	changePasswordCmd.Flags().String("new-password", "", "New password for the PDF files.")
+	changePasswordCmd.Flags().StringP("name", "n", "", "Add a prefix to the beginning of the file name.")
+	changePasswordCmd.Flags().Bool("recursive", false, "Search the directories for PDF files recursively.")
+	changePasswordCmd.Flags().StringSlice("include", nil, "Only use the PDF files in the directories that match one of these globs.")
+	changePasswordCmd.Flags().StringSlice("exclude", nil, "Skip the PDF files in the directories that match one of these globs.")
+	changePasswordCmd.Flags().String("output-dir", "", "Directory to write the PDF files to, it's created when it doesn't exist.")
+	changePasswordCmd.Flags().String("name-template", "", nameTemplateUsage)
+	changePasswordCmd.Flags().BoolP("force", "f", false, "Overwrite files that already exist.")
+	changePasswordCmd.Flags().Bool("backup", false, "Keep a copy of every file that is overwritten, restore it with the undo command.")
+	changePasswordCmd.Flags().BoolP("keep-going", "k", false, "Keep going when a file fails and print a summary at the end.")
+	changePasswordCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of PDF files to process at the same time.")
+	changePasswordCmd.Flags().Bool("in-place", false, "Overwrite the original PDF files when there is no prefix or output directory.")
+
+	// autocomplete for files
+	changePasswordCmd.ValidArgsFunction = autocomplete.GetSuggestions
+}
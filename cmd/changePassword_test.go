@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/stretchr/testify/assert"
+)
+
+// Only testing non interactive mode for now
+func TestChangePasswordCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		pdfs           []string
+		flags          []string
+		fileOutput     string
+		expectError    bool
+		exitCode       int
+		expectedOutput string
+		checkFile      bool
+		encrypt        bool
+		password       string
+	}{
+		{
+			name:           "Change the password of a PDF file",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{changePassword, "file1.pdf", "--old-password", "test", "--new-password", "new", "--in-place"},
+			fileOutput:     "file1.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file password changed successfully to:",
+			checkFile:      true,
+			encrypt:        true,
+			password:       "test",
+		},
+		{
+			name:           "Change the password of a PDF file with the rekey alias and a prefix",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{"rekey", "file1.pdf", "--old-password", "test", "--new-password", "new", "-n", "new-"},
+			fileOutput:     "new-file1.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file password changed successfully to:",
+			checkFile:      true,
+			encrypt:        true,
+			password:       "test",
+		},
+		{
+			name:           "Wrong old password",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{changePassword, "file1.pdf", "--old-password", "wrong", "--new-password", "new"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitWrongPassword,
+			expectedOutput: "please provide the correct password",
+			checkFile:      false,
+			encrypt:        true,
+			password:       "test",
+		},
+		{
+			name:           "Check if files are available",
+			pdfs:           nil,
+			flags:          []string{changePassword, "file1.pdf", "--old-password", "test", "--new-password", "new", "--in-place"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitIO,
+			expectedOutput: "no such file or directory",
+			checkFile:      false,
+			encrypt:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t, changePasswordCmd)
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+			createTestFiles(t, tempDir, tt.pdfs)
+			if tt.encrypt && tt.password != "" {
+				encryptTestFiles(t, tempDir, tt.pdfs, tt.password, "")
+			}
+
+			var outputBuf bytes.Buffer
+
+			rootCmd.SetOut(&outputBuf)
+			rootCmd.SetErr(&outputBuf)
+			rootCmd.SetArgs(tt.flags)
+
+			err = rootCmd.Execute()
+
+			if tt.expectError {
+				assert.ErrorContains(t, err, tt.expectedOutput, "Expected an error but command ran successfully.")
+			} else {
+				assert.NoError(t, err, "Expected command to run successfully but it failed.")
+				assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Expected output to contain: %s", tt.expectedOutput)
+			}
+			assert.Equal(t, tt.exitCode, exitCodeFor(err), "Unexpected exit code.")
+
+			if tt.checkFile {
+				_, err := pdf.NewPDFProcessor(decrypt).InPlace().DecryptPdf(tt.fileOutput, tempDir, "new", "")
+				assert.NoError(t, err, "Expected %s to open with the new password.", tt.fileOutput)
+			}
+		})
+	}
+}
+
+func TestChangePasswordCommandKeepGoing(t *testing.T) {
+	resetFlags(t, changePasswordCmd)
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+	createTestFiles(t, tempDir, []string{"file1.pdf", "file2.pdf", "file3.pdf"})
+	encryptTestFiles(t, tempDir, []string{"file1.pdf", "file3.pdf"}, "old", "")
+	encryptTestFiles(t, tempDir, []string{"file2.pdf"}, "other", "")
+
+	var outputBuf bytes.Buffer
+
+	rootCmd.SetOut(&outputBuf)
+	rootCmd.SetErr(&outputBuf)
+	rootCmd.SetArgs([]string{changePassword, "file1.pdf", "file2.pdf", "file3.pdf", "--old-password", "old", "--new-password", "new", "-k", "--in-place"})
+
+	err = rootCmd.Execute()
+	assert.EqualError(t, err, "failed to change the password of 1 of 3 PDF files")
+	assert.Equal(t, exitWrongPassword, exitCodeFor(err), "Expected the exit code of a wrong password")
+
+	output := outputBuf.String()
+	assert.Contains(t, output, "PDF file password changed successfully to: "+tempDir+"/file1.pdf")
+	assert.Contains(t, output, "PDF file password changed successfully to: "+tempDir+"/file3.pdf")
+	assert.Contains(t, output, "2 succeeded, 1 failed")
+}
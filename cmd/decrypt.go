@@ -4,9 +4,10 @@ Copyright © 2025 NAME HERE Aito Nakajima
 package cmd
 
 import (
+	"runtime"
+
 	"github.com/gmskazi/pdfmc/cmd/autocomplete"
 	"github.com/gmskazi/pdfmc/cmd/program"
-	"github.com/gmskazi/pdfmc/cmd/styles"
 	"github.com/spf13/cobra"
 )
 
@@ -16,12 +17,10 @@ var decryptCmd = &cobra.Command{
 	Short: "Decrypt PDF files.",
 	Long:  `This is a tool to decrypt pdf files.`,
 	Args:  cobra.ArbitraryArgs,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
 		p := program.NewProgram(cmd, args, decrypt)
-		if err := p.ExecuteDecrypt(); err != nil {
-			cmd.PrintErrln(styles.ErrorStyle.Render(err.Error()))
-			return
-		}
+		return p.ExecuteDecrypt()
 	},
 }
 
@@ -29,7 +28,21 @@ func init() {
 	rootCmd.AddCommand(decryptCmd)
 
 	decryptCmd.Flags().StringP("password", "p", "", "Password to decrypt the PDF files.")
+	decryptCmd.Flags().String("password-file", "", "Read the password to decrypt the PDF files from a file.")
+	decryptCmd.Flags().Bool("password-stdin", false, "Read the password to decrypt the PDF files from stdin.")
+	decryptCmd.Flags().String("password-env", "", "Read the password to decrypt the PDF files from an environment variable.")
+	decryptCmd.Flags().String("password-map", "", "CSV or JSON file mapping file names or globs to their passwords.")
 	decryptCmd.Flags().StringP("name", "n", "", "Add a prefix to the beginning of the file name.")
+	decryptCmd.Flags().Bool("recursive", false, "Search the directories for PDF files recursively.")
+	decryptCmd.Flags().StringSlice("include", nil, "Only use the PDF files in the directories that match one of these globs.")
+	decryptCmd.Flags().StringSlice("exclude", nil, "Skip the PDF files in the directories that match one of these globs.")
+	decryptCmd.Flags().String("output-dir", "", "Directory to write the PDF files to, it's created when it doesn't exist.")
+	decryptCmd.Flags().String("name-template", "", nameTemplateUsage)
+	decryptCmd.Flags().BoolP("force", "f", false, "Overwrite files that already exist.")
+	decryptCmd.Flags().Bool("backup", false, "Keep a copy of every file that is overwritten, restore it with the undo command.")
+	decryptCmd.Flags().Bool("in-place", false, "Overwrite the original PDF files when there is no prefix or output directory.")
+	decryptCmd.Flags().BoolP("keep-going", "k", false, "Keep going when a file fails and print a summary at the end.")
+	decryptCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of PDF files to process at the same time.")
 	// autocomplete for files
 	decryptCmd.ValidArgsFunction = autocomplete.GetSuggestions
 
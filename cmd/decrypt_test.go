@@ -11,7 +11,7 @@ import (
 )
 
 func encryptTestFiles(t *testing.T, tempdir string, pdfs []string, password, pdfPrefix string) {
-	p := pdf.NewPDFProcessor(encrypt)
+	p := pdf.NewPDFProcessor(encrypt).InPlace()
 	// encrypt test files
 	for _, f := range pdfs {
 
@@ -31,6 +31,7 @@ func TestDecryptCommand(t *testing.T) {
 		flags          []string
 		fileOutput     string
 		expectError    bool
+		exitCode       int
 		expectedOutput string
 		checkFile      bool
 		encrypt        bool
@@ -40,7 +41,7 @@ func TestDecryptCommand(t *testing.T) {
 			name:           "Decrypt a PDF file",
 			pdfs:           []string{"file1.pdf"},
 			pdfPrefix:      "",
-			flags:          []string{decrypt, "file1.pdf", "-p", "test"},
+			flags:          []string{decrypt, "file1.pdf", "-p", "test", "--in-place"},
 			fileOutput:     "file1.pdf",
 			expectError:    false,
 			expectedOutput: "PDF file decrypted successfully to:",
@@ -51,9 +52,10 @@ func TestDecryptCommand(t *testing.T) {
 		{
 			name:           "Check if file is not encrypted",
 			pdfs:           []string{"file1.pdf"},
-			flags:          []string{decrypt, "file1.pdf", "-p", "test"},
+			flags:          []string{decrypt, "file1.pdf", "-p", "test", "--in-place"},
 			fileOutput:     "",
-			expectError:    false,
+			expectError:    true,
+			exitCode:       exitInvalidPDF,
 			expectedOutput: "this file is not encrypted",
 			checkFile:      false,
 			encrypt:        false,
@@ -65,6 +67,7 @@ func TestDecryptCommand(t *testing.T) {
 			flags:          []string{decrypt, "file1.pdf", "-p"},
 			fileOutput:     "",
 			expectError:    true,
+			exitCode:       exitUsage,
 			expectedOutput: "flag needs an argument:",
 			checkFile:      false,
 			encrypt:        false,
@@ -74,14 +77,40 @@ func TestDecryptCommand(t *testing.T) {
 			name:           "Check if files are available",
 			pdfs:           nil,
 			pdfPrefix:      "",
-			flags:          []string{decrypt, "file1.pdf", "-p", "test"},
+			flags:          []string{decrypt, "file1.pdf", "-p", "test", "--in-place"},
 			fileOutput:     "",
-			expectError:    false,
+			expectError:    true,
+			exitCode:       exitIO,
 			expectedOutput: "no such file or directory",
 			checkFile:      false,
 			encrypt:        false,
 			password:       "",
 		},
+		{
+			name:           "Decrypt a PDF file with the password from an environment variable",
+			pdfs:           []string{"file1.pdf"},
+			pdfPrefix:      "",
+			flags:          []string{decrypt, "file1.pdf", "--password-env", "PDFMC_TEST_PASSWORD", "--in-place"},
+			fileOutput:     "file1.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file decrypted successfully to:",
+			checkFile:      true,
+			encrypt:        true,
+			password:       "test",
+		},
+		{
+			name:           "More than one password source",
+			pdfs:           []string{"file1.pdf"},
+			pdfPrefix:      "",
+			flags:          []string{decrypt, "file1.pdf", "-p", "test", "--password-env", "PDFMC_TEST_PASSWORD", "--in-place"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "please provide only one password source",
+			checkFile:      false,
+			encrypt:        true,
+			password:       "test",
+		},
 		{
 			name:           "decrypt file with custom name prefix",
 			pdfs:           []string{"file1.pdf"},
@@ -96,8 +125,11 @@ func TestDecryptCommand(t *testing.T) {
 		},
 	}
 
+	t.Setenv("PDFMC_TEST_PASSWORD", "test")
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t, decryptCmd)
 			tempDir := t.TempDir()
 			err := os.Chdir(tempDir)
 			assert.NoError(t, err, "failed to change directory: ", tempDir)
@@ -117,12 +149,12 @@ func TestDecryptCommand(t *testing.T) {
 			err = rootCmd.Execute()
 
 			if tt.expectError {
-				assert.Error(t, err, "Expected an error but command ran successfully.")
+				assert.ErrorContains(t, err, tt.expectedOutput, "Expected an error but command ran successfully.")
 			} else {
 				assert.NoError(t, err, "Expected command to run successfully but it failed.")
+				assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Expected output to contain: %s", tt.expectedOutput)
 			}
-
-			assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Expected output to contain: %s", tt.expectedOutput)
+			assert.Equal(t, tt.exitCode, exitCodeFor(err), "Unexpected exit code.")
 
 			if tt.checkFile {
 				_, err := os.Stat(tt.fileOutput)
@@ -131,3 +163,59 @@ func TestDecryptCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestDecryptCommandPasswordMap(t *testing.T) {
+	resetFlags(t, decryptCmd)
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+	createTestFiles(t, tempDir, []string{"vendor-a.pdf", "vendor-b.pdf", "vendor-c.pdf"})
+	encryptTestFiles(t, tempDir, []string{"vendor-a.pdf"}, "secretA", "")
+	encryptTestFiles(t, tempDir, []string{"vendor-b.pdf"}, "secretB", "")
+	encryptTestFiles(t, tempDir, []string{"vendor-c.pdf"}, "unknown", "")
+
+	err = os.WriteFile("passwords.csv", []byte("vendor-a.pdf,secretA\nvendor-b*.pdf,secretB\n"), 0600)
+	assert.NoError(t, err, "failed to create the password map")
+
+	var outputBuf bytes.Buffer
+
+	rootCmd.SetOut(&outputBuf)
+	rootCmd.SetErr(&outputBuf)
+	rootCmd.SetArgs([]string{decrypt, "vendor-a.pdf", "vendor-c.pdf", "vendor-b.pdf", "--password-map", "passwords.csv", "--in-place"})
+
+	err = rootCmd.Execute()
+	assert.EqualError(t, err, "failed to decrypt 1 of 3 PDF files")
+	assert.Equal(t, exitWrongPassword, exitCodeFor(err), "Expected the exit code of a wrong password")
+
+	output := outputBuf.String()
+	assert.Contains(t, output, "PDF file decrypted successfully to: "+tempDir+"/vendor-a.pdf")
+	assert.Contains(t, output, "PDF file decrypted successfully to: "+tempDir+"/vendor-b.pdf")
+	assert.Contains(t, output, "vendor-c.pdf: pdfcpu: please provide the correct password")
+}
+
+func TestDecryptCommandKeepGoing(t *testing.T) {
+	resetFlags(t, decryptCmd)
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+	createTestFiles(t, tempDir, []string{"file1.pdf", "file2.pdf", "file3.pdf"})
+	encryptTestFiles(t, tempDir, []string{"file1.pdf", "file3.pdf"}, "test", "")
+
+	var outputBuf bytes.Buffer
+
+	rootCmd.SetOut(&outputBuf)
+	rootCmd.SetErr(&outputBuf)
+	rootCmd.SetArgs([]string{decrypt, "file1.pdf", "file2.pdf", "file3.pdf", "-p", "test", "--keep-going", "--in-place"})
+
+	err = rootCmd.Execute()
+	assert.EqualError(t, err, "failed to decrypt 1 of 3 PDF files")
+	assert.Equal(t, exitInvalidPDF, exitCodeFor(err), "Expected the exit code of an invalid PDF")
+
+	output := outputBuf.String()
+	assert.Contains(t, output, "PDF file decrypted successfully to: "+tempDir+"/file1.pdf")
+	assert.Contains(t, output, "PDF file decrypted successfully to: "+tempDir+"/file3.pdf")
+	assert.Contains(t, output, "file2.pdf: pdfcpu: this file is not encrypted")
+	assert.Contains(t, output, "2 succeeded, 1 failed")
+}
@@ -4,9 +4,11 @@ Copyright © 2025 NAME HERE Aito Nakajima
 package cmd
 
 import (
+	"runtime"
+
 	"github.com/gmskazi/pdfmc/cmd/autocomplete"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
 	"github.com/gmskazi/pdfmc/cmd/program"
-	"github.com/gmskazi/pdfmc/cmd/styles"
 	"github.com/spf13/cobra"
 )
 
@@ -16,12 +18,10 @@ var encryptCmd = &cobra.Command{
 	Short: "Encrypt PDF files.",
 	Long:  `This is a tool for encrypting PDF files.`,
 	Args:  cobra.ArbitraryArgs,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
 		p := program.NewProgram(cmd, args, encrypt)
-		if err := p.ExecuteEncrypt(); err != nil {
-			cmd.PrintErrln(styles.ErrorStyle.Render(err.Error()))
-			return
-		}
+		return p.ExecuteEncrypt()
 	},
 }
 
@@ -29,7 +29,27 @@ func init() {
 	rootCmd.AddCommand(encryptCmd)
 
 	encryptCmd.Flags().StringP("password", "p", "", "Password to encrypt the PDF files.")
+	encryptCmd.Flags().String("password-file", "", "Read the password to encrypt the PDF files from a file.")
+	encryptCmd.Flags().Bool("password-stdin", false, "Read the password to encrypt the PDF files from stdin.")
+	encryptCmd.Flags().String("password-env", "", "Read the password to encrypt the PDF files from an environment variable.")
 	encryptCmd.Flags().StringP("name", "n", "", "Add a prefix to the beginning of the file name.")
+	encryptCmd.Flags().Bool("recursive", false, "Search the directories for PDF files recursively.")
+	encryptCmd.Flags().StringSlice("include", nil, "Only use the PDF files in the directories that match one of these globs.")
+	encryptCmd.Flags().StringSlice("exclude", nil, "Skip the PDF files in the directories that match one of these globs.")
+	encryptCmd.Flags().String("output-dir", "", "Directory to write the PDF files to, it's created when it doesn't exist.")
+	encryptCmd.Flags().String("name-template", "", nameTemplateUsage)
+	encryptCmd.Flags().BoolP("force", "f", false, "Overwrite files that already exist.")
+	encryptCmd.Flags().Bool("backup", false, "Keep a copy of every file that is overwritten, restore it with the undo command.")
+	encryptCmd.Flags().Bool("in-place", false, "Overwrite the original PDF files when there is no prefix or output directory.")
+	encryptCmd.Flags().BoolP("keep-going", "k", false, "Keep going when a file fails and print a summary at the end.")
+	encryptCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of PDF files to process at the same time.")
+	encryptCmd.Flags().String("user-password", "", "Password needed to open the PDF files.")
+	encryptCmd.Flags().String("owner-password", "", "Password needed to change the permissions of the PDF files.")
+	encryptCmd.Flags().String("permissions", "", permissionsUsage)
+	encryptCmd.Flags().String("algorithm", pdf.AlgorithmAES, "Encryption algorithm, aes or rc4.")
+	encryptCmd.Flags().Int("key-length", 0, "Encryption key length, 128 or 256 for aes and 40 or 128 for rc4, defaults to the strongest.")
+	encryptCmd.Flags().String("watermark", "", "Stamp this text on the PDF files before they're encrypted.")
+	encryptCmd.Flags().String("watermark-image", "", "Stamp this image on the PDF files before they're encrypted.")
 
 	// autocomplete for files flag
 	mergeCmd.ValidArgsFunction = autocomplete.GetSuggestions
@@ -3,6 +3,8 @@ package cmd
 import (
 	"bytes"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,6 +18,7 @@ func TestEncryptCommand(t *testing.T) {
 		flags          []string
 		fileOutput     string
 		expectError    bool
+		exitCode       int
 		expectedOutput string
 		checkFile      bool
 		encrypt        bool
@@ -24,7 +27,7 @@ func TestEncryptCommand(t *testing.T) {
 		{
 			name:           "Encrypt a PDF file",
 			pdfs:           []string{"file1.pdf"},
-			flags:          []string{encrypt, "file1.pdf", "-p", "test"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--in-place"},
 			fileOutput:     "file1.pdf",
 			expectError:    false,
 			expectedOutput: "PDF file encrypted successfully to:",
@@ -33,9 +36,10 @@ func TestEncryptCommand(t *testing.T) {
 		{
 			name:           "Check if file is encrypted",
 			pdfs:           []string{"file1.pdf"},
-			flags:          []string{encrypt, "file1.pdf", "-p", "test"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--in-place"},
 			fileOutput:     "",
-			expectError:    false,
+			expectError:    true,
+			exitCode:       exitInvalidPDF,
 			expectedOutput: "this file is already encrypted",
 			checkFile:      false,
 			encrypt:        true,
@@ -47,15 +51,17 @@ func TestEncryptCommand(t *testing.T) {
 			flags:          []string{encrypt, "file1.pdf", "-p"},
 			fileOutput:     "",
 			expectError:    true,
+			exitCode:       exitUsage,
 			expectedOutput: "flag needs an argument:",
 			checkFile:      false,
 		},
 		{
 			name:           "Check if files are available",
 			pdfs:           nil,
-			flags:          []string{encrypt, "file1.pdf", "-p", "test"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--in-place"},
 			fileOutput:     "",
-			expectError:    false,
+			expectError:    true,
+			exitCode:       exitIO,
 			expectedOutput: "no such file or directory",
 			checkFile:      false,
 		},
@@ -68,10 +74,173 @@ func TestEncryptCommand(t *testing.T) {
 			expectedOutput: "PDF file encrypted successfully to:",
 			checkFile:      true,
 		},
+		{
+			name:           "Encrypt PDF file with the password from stdin",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "--password-stdin", "--in-place"},
+			fileOutput:     "file1.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Encrypt PDF file with owner password and permissions",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "--owner-password", "owner", "--permissions", "print,copy", "--in-place"},
+			fileOutput:     "file1.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Encrypt PDF file with user and owner passwords",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "--user-password", "user", "--owner-password", "owner", "--in-place"},
+			fileOutput:     "file1.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Encrypt PDF file with rc4",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--algorithm", "rc4", "--key-length", "40", "--in-place"},
+			fileOutput:     "file1.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Encrypt PDF file with a watermark",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--watermark", "CONFIDENTIAL", "-n", "stamped-"},
+			fileOutput:     "stamped-file1.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Text and image watermark",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--watermark", "CONFIDENTIAL", "--watermark-image", "logo.png", "--in-place"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "please provide either the --watermark flag or the --watermark-image flag",
+			checkFile:      false,
+		},
+		{
+			name:           "Page selection is rejected",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf[1]", "-p", "test"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "page selections like file1.pdf[1] are only supported when merging",
+			checkFile:      false,
+		},
+		{
+			name:           "Invalid key length for the algorithm",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--algorithm", "rc4", "--key-length", "256"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "rc4 encryption doesn't support a key length of 256",
+			checkFile:      false,
+		},
+		{
+			name:           "Unknown permission",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--permissions", "delete"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "unknown permission",
+			checkFile:      false,
+		},
+		{
+			name:           "Password and user password provided",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--user-password", "user"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "please provide either the --password flag or the --user-password flag",
+			checkFile:      false,
+		},
+		{
+			name:           "Encrypt PDF files on several workers",
+			pdfs:           []string{"file1.pdf", "file2.pdf", "file3.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "file2.pdf", "file3.pdf", "-p", "test", "-j", "2", "--in-place"},
+			fileOutput:     "file3.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Encrypt PDF files into an output directory with a name template",
+			pdfs:           []string{"file1.pdf", "file2.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "file2.pdf", "-p", "test", "--output-dir", "encrypted", "--name-template", "{index}_{stem}_{op}"},
+			fileOutput:     "encrypted/2_file2_encrypt.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Unknown name template token",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--name-template", "{stem}_{time}"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "unknown name template token {time}",
+			checkFile:      false,
+		},
+		{
+			name:           "Refuse to overwrite the original PDF file",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "use --in-place to overwrite the original PDF files",
+			checkFile:      false,
+		},
+		{
+			name:           "Refuse to overwrite an existing file",
+			pdfs:           []string{"file1.pdf", "testfile1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "-n", "test"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "testfile1.pdf already exists, use --force to overwrite it",
+			checkFile:      false,
+		},
+		{
+			name:           "Overwrite an existing file with force",
+			pdfs:           []string{"file1.pdf", "testfile1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "-n", "test", "--force"},
+			fileOutput:     "testfile1.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Invalid number of jobs",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{encrypt, "file1.pdf", "-p", "test", "--jobs", "0"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "the --jobs flag must be at least 1",
+			checkFile:      false,
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t, encryptCmd)
 			tempDir := t.TempDir()
 			err := os.Chdir(tempDir)
 			assert.NoError(t, err, "failed to change directory: ", tempDir)
@@ -84,6 +253,7 @@ func TestEncryptCommand(t *testing.T) {
 
 			var outputBuf bytes.Buffer
 
+			rootCmd.SetIn(strings.NewReader("test\n"))
 			rootCmd.SetOut(&outputBuf)
 			rootCmd.SetErr(&outputBuf)
 			rootCmd.SetArgs(args)
@@ -91,12 +261,12 @@ func TestEncryptCommand(t *testing.T) {
 			err = rootCmd.Execute()
 
 			if tt.expectError {
-				assert.Error(t, err, "Expected an error but command ran successfully.")
+				assert.ErrorContains(t, err, tt.expectedOutput, "Expected an error but command ran successfully.")
 			} else {
 				assert.NoError(t, err, "Expected command to run successfully but it failed.")
+				assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Expected output to contain: %s", tt.expectedOutput)
 			}
-
-			assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Expected output to contain: %s", tt.expectedOutput)
+			assert.Equal(t, tt.exitCode, exitCodeFor(err), "Unexpected exit code.")
 
 			if tt.checkFile {
 				_, err := os.Stat(tt.fileOutput)
@@ -105,3 +275,97 @@ func TestEncryptCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestEncryptCommandKeepGoing(t *testing.T) {
+	resetFlags(t, encryptCmd)
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+	createTestFiles(t, tempDir, []string{"file1.pdf", "file2.pdf", "file3.pdf"})
+	encryptTestFiles(t, tempDir, []string{"file2.pdf"}, "test", "")
+
+	var outputBuf bytes.Buffer
+
+	rootCmd.SetOut(&outputBuf)
+	rootCmd.SetErr(&outputBuf)
+	rootCmd.SetArgs([]string{encrypt, "file1.pdf", "file2.pdf", "file3.pdf", "-p", "test", "-k", "--in-place"})
+
+	err = rootCmd.Execute()
+	assert.EqualError(t, err, "failed to encrypt 1 of 3 PDF files")
+	assert.Equal(t, exitInvalidPDF, exitCodeFor(err), "Expected the exit code of an invalid PDF")
+
+	output := outputBuf.String()
+	assert.Contains(t, output, "PDF file encrypted successfully to: "+tempDir+"/file1.pdf")
+	assert.Contains(t, output, "PDF file encrypted successfully to: "+tempDir+"/file3.pdf")
+	assert.Contains(t, output, "2 succeeded, 1 failed")
+}
+
+func TestEncryptCommandRecursive(t *testing.T) {
+	resetFlags(t, encryptCmd)
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+	err = os.MkdirAll(filepath.Join("invoices", "2024", "q1"), 0755)
+	assert.NoError(t, err)
+	createTestFiles(t, tempDir, []string{
+		"file1.pdf",
+		filepath.Join("invoices", "a.pdf"),
+		filepath.Join("invoices", "2024", "b.pdf"),
+		filepath.Join("invoices", "2024", "q1", "c.pdf"),
+		filepath.Join("invoices", "2024", "q1", "draft-d.pdf"),
+	})
+
+	var outputBuf bytes.Buffer
+
+	rootCmd.SetOut(&outputBuf)
+	rootCmd.SetErr(&outputBuf)
+	rootCmd.SetArgs([]string{encrypt, "file1.pdf", "invoices", "-p", "test", "--recursive", "--exclude", "draft-*", "--output-dir", "encrypted"})
+
+	err = rootCmd.Execute()
+	assert.NoError(t, err, "Expected command to run successfully but it failed.")
+
+	for _, file := range []string{
+		filepath.Join("encrypted", "file1.pdf"),
+		filepath.Join("encrypted", "a.pdf"),
+		filepath.Join("encrypted", "2024", "b.pdf"),
+		filepath.Join("encrypted", "2024", "q1", "c.pdf"),
+	} {
+		_, err := os.Stat(file)
+		assert.NoError(t, err, "Expected file %s to be created but it was not found.", file)
+	}
+
+	_, err = os.Stat(filepath.Join("encrypted", "2024", "q1", "draft-d.pdf"))
+	assert.True(t, os.IsNotExist(err), "Expected the excluded file to be skipped")
+}
+
+func TestEncryptCommandGlobOutputDir(t *testing.T) {
+	resetFlags(t, encryptCmd)
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+	for _, sub := range []string{"a", "b"} {
+		err = os.MkdirAll(filepath.Join("docs", sub), 0755)
+		assert.NoError(t, err)
+	}
+	createTestFiles(t, tempDir, []string{filepath.Join("docs", "a", "report.pdf"), filepath.Join("docs", "b", "report.pdf")})
+
+	var outputBuf bytes.Buffer
+
+	rootCmd.SetOut(&outputBuf)
+	rootCmd.SetErr(&outputBuf)
+	rootCmd.SetArgs([]string{encrypt, "docs/**/*.pdf", "-p", "test", "--output-dir", "encrypted"})
+
+	err = rootCmd.Execute()
+	assert.NoError(t, err, "Expected command to run successfully but it failed.")
+
+	for _, file := range []string{
+		filepath.Join("encrypted", "a", "report.pdf"),
+		filepath.Join("encrypted", "b", "report.pdf"),
+	} {
+		_, err := os.Stat(file)
+		assert.NoError(t, err, "Expected file %s to be created but it was not found.", file)
+	}
+}
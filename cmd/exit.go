@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+// Exit codes, see the README for when each of them is used.
+const (
+	exitOK            = 0
+	exitFailure       = 1
+	exitUsage         = 2
+	exitWrongPassword = 3
+	exitInvalidPDF    = 4
+	exitIO            = 5
+	exitCanceled      = 130
+)
+
+// exitCodeFor picks the first matching exit code when a batch failed for
+// different reasons.
+func exitCodeFor(err error) int {
+	var (
+		usageErr     *utils.UsageError
+		pageRangeErr *pdf.PageRangeError
+		pathErr      *fs.PathError
+	)
+
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, utils.ErrCanceled):
+		return exitCanceled
+	case errors.As(err, &usageErr), errors.As(err, &pageRangeErr), pdf.IsOutputExists(err):
+		return exitUsage
+	case pdf.IsWrongPassword(err):
+		return exitWrongPassword
+	case errors.Is(err, pdf.ErrInvalidPDF):
+		return exitInvalidPDF
+	case errors.As(err, &pathErr):
+		return exitIO
+	default:
+		return exitFailure
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/gmskazi/pdfmc/cmd/utils"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCodeFor(t *testing.T) {
+	_, pathErr := os.Stat("missing.pdf")
+
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{
+			name:     "no error",
+			err:      nil,
+			expected: exitOK,
+		},
+		{
+			name:     "canceled by the user",
+			err:      utils.ErrCanceled,
+			expected: exitCanceled,
+		},
+		{
+			name:     "bad arguments",
+			err:      utils.NewUsageError("please provide a password"),
+			expected: exitUsage,
+		},
+		{
+			name:     "wrong password",
+			err:      fmt.Errorf("file1.pdf: %w", pdfcpu.ErrWrongPassword),
+			expected: exitWrongPassword,
+		},
+		{
+			name:     "I/O error",
+			err:      pathErr,
+			expected: exitIO,
+		},
+		{
+			name:     "any other error",
+			err:      errors.New("something went wrong"),
+			expected: exitFailure,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, exitCodeFor(tt.err))
+		})
+	}
+}
+
+func TestExitCodeForCommandLine(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+	}{
+		{
+			name:           "unknown command",
+			args:           []string{"nosuchcmd"},
+			expectedOutput: `unknown command "nosuchcmd" for "pdfmc"`,
+		},
+		{
+			name:           "unexpected positional argument",
+			args:           []string{undo, "file1.pdf"},
+			expectedOutput: `unknown command "file1.pdf" for "pdfmc undo"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t, undoCmd)
+			rootCmd.SetArgs(tt.args)
+
+			_, err := executeC()
+			assert.ErrorContains(t, err, tt.expectedOutput)
+			assert.Equal(t, exitUsage, exitCodeFor(err), "Unexpected exit code.")
+		})
+	}
+}
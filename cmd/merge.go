@@ -4,32 +4,47 @@ Copyright © 2025 Aito Nakajima
 package cmd
 
 import (
+	"strings"
+
 	"github.com/gmskazi/pdfmc/cmd/autocomplete"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
 	"github.com/gmskazi/pdfmc/cmd/program"
-	"github.com/gmskazi/pdfmc/cmd/styles"
 	"github.com/spf13/cobra"
 )
 
 const (
-	merge   = "merge"
-	encrypt = "encrypt"
-	decrypt = "decrypt"
+	merge     = "merge"
+	encrypt   = "encrypt"
+	decrypt   = "decrypt"
+	split     = "split"
+	rotate    = "rotate"
+	optimize  = "optimize"
+	watermark = "watermark"
+	undo      = "undo"
+
+	changePassword = "change-password"
 )
 
 var name string
 
+var permissionsUsage = "Comma separated permissions granted with the user password: " + strings.Join(pdf.PermissionNames(), ", ")
+
+var sortUsage = "Sort the PDF files before merging by one of: " + strings.Join(program.SortOrders, ", ")
+
+var nameTemplateUsage = "Template for the output file names with the tokens: " + strings.Join(pdf.NameTokens, ", ")
+
+var dpiUsage = "Downsample the images that are sharper than this many dots per inch across the page, 0 keeps them as they are."
+
 // mergeCmd represents the merge command
 var mergeCmd = &cobra.Command{
 	Use:   "merge [files... or folder]",
 	Short: "Merge PDFs together.",
 	Long:  `This is a tool to merge PDFs together.`,
 	Args:  cobra.ArbitraryArgs,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
 		p := program.NewProgram(cmd, args, merge)
-		if err := p.ExecuteMerge(); err != nil {
-			cmd.PrintErrln(styles.ErrorStyle.Render(err.Error()))
-			return
-		}
+		return p.ExecuteMerge()
 	},
 }
 
@@ -37,9 +52,31 @@ func init() {
 	rootCmd.AddCommand(mergeCmd)
 
 	mergeCmd.Flags().StringVarP(&name, "name", "n", "merged_output", "Custom name for the merged PDF files")
+	mergeCmd.Flags().Bool("recursive", false, "Search the directories for PDF files recursively.")
+	mergeCmd.Flags().StringSlice("include", nil, "Only use the PDF files in the directories that match one of these globs.")
+	mergeCmd.Flags().StringSlice("exclude", nil, "Skip the PDF files in the directories that match one of these globs.")
+	mergeCmd.Flags().String("output-dir", "", "Directory to write the PDF files to, it's created when it doesn't exist.")
+	mergeCmd.Flags().String("name-template", "", nameTemplateUsage)
+	mergeCmd.Flags().BoolP("force", "f", false, "Overwrite files that already exist.")
+	mergeCmd.Flags().Bool("backup", false, "Keep a copy of every file that is overwritten, restore it with the undo command.")
 	mergeCmd.Flags().StringP("password", "p", "", "Password to encrypt the PDF file.")
+	mergeCmd.Flags().String("password-file", "", "Read the password to encrypt the PDF file from a file.")
+	mergeCmd.Flags().Bool("password-stdin", false, "Read the password to encrypt the PDF file from stdin.")
+	mergeCmd.Flags().String("password-env", "", "Read the password to encrypt the PDF file from an environment variable.")
 	mergeCmd.Flags().BoolP("order", "o", false, "Reorder the PDF files before merging.")
+	mergeCmd.Flags().Bool("page-mode", false, "Reorder, delete, duplicate and rotate single pages before merging.")
+	mergeCmd.Flags().String("sort", "", sortUsage)
+	mergeCmd.Flags().Bool("reverse", false, "Merge the PDF files in reverse order.")
+	mergeCmd.Flags().Bool("optimize", false, "Optimize the merged PDF file before it's encrypted.")
+	mergeCmd.Flags().Int("dpi", 0, dpiUsage)
+	mergeCmd.Flags().String("watermark", "", "Stamp this text on the merged PDF file before it's encrypted.")
+	mergeCmd.Flags().String("watermark-image", "", "Stamp this image on the merged PDF file before it's encrypted.")
 	mergeCmd.Flags().BoolP("encrypt", "e", false, "Encrypt the PDF file interatively.")
+	mergeCmd.Flags().String("user-password", "", "Password needed to open the PDF file.")
+	mergeCmd.Flags().String("owner-password", "", "Password needed to change the permissions of the PDF file.")
+	mergeCmd.Flags().String("permissions", "", permissionsUsage)
+	mergeCmd.Flags().String("algorithm", pdf.AlgorithmAES, "Encryption algorithm, aes or rc4.")
+	mergeCmd.Flags().Int("key-length", 0, "Encryption key length, 128 or 256 for aes and 40 or 128 for rc4, defaults to the strongest.")
 
 	// autocomplete for files flag
 	mergeCmd.ValidArgsFunction = autocomplete.GetSuggestions
@@ -61,6 +61,7 @@ func TestMergeCommand(t *testing.T) {
 		flags          []string
 		fileOutput     string
 		expectError    bool
+		exitCode       int
 		expectedOutput string
 		checkFile      bool
 	}{
@@ -82,6 +83,24 @@ func TestMergeCommand(t *testing.T) {
 			expectedOutput: "PDF files merged successfully to:",
 			checkFile:      true,
 		},
+		{
+			name:           "Merge two PDF files into an output directory",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1, file2, "--output-dir", "out", "--name-template", "{stem}_{pages}"},
+			fileOutput:     "out/merged_output_1-2.pdf",
+			expectError:    false,
+			expectedOutput: "PDF files merged successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Merge and encrypt two PDF files into an output directory",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1, file2, "--output-dir", "out", "-p", "test"},
+			fileOutput:     "out/merged_output.pdf",
+			expectError:    false,
+			expectedOutput: "PDF files merged and encrypted successfully to:",
+			checkFile:      true,
+		},
 		{
 			name:           "Merge two PDF files with custom filename and password",
 			pdfs:           []string{file1, file2},
@@ -91,14 +110,138 @@ func TestMergeCommand(t *testing.T) {
 			expectedOutput: "PDF files merged and encrypted successfully to:",
 			checkFile:      true,
 		},
+		{
+			name:           "Merge two PDF files with page selections",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1 + "[1]", file2 + "[1-]", "-n", "pages"},
+			fileOutput:     "pages.pdf",
+			expectError:    false,
+			expectedOutput: "PDF files merged successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Merge two PDF files in natural order",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file2, file1, "--sort", "natural", "--reverse", "-n", "sorted"},
+			fileOutput:     "sorted.pdf",
+			expectError:    false,
+			expectedOutput: "PDF files merged successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Merge two PDF files with an unknown sort order",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1, file2, "--sort", "pages"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "unknown sort order pages",
+			checkFile:      false,
+		},
+		{
+			name:           "Merge two PDF files with an owner password and permissions",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1, file2, "--owner-password", "owner", "--permissions", "print"},
+			fileOutput:     "merged_output.pdf",
+			expectError:    false,
+			expectedOutput: "PDF files merged and encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Merge two PDF files and encrypt with aes 128",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1, file2, "-p", "test", "--key-length", "128"},
+			fileOutput:     "merged_output.pdf",
+			expectError:    false,
+			expectedOutput: "PDF files merged and encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Merge, optimize and encrypt two PDF files",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1, file2, "--optimize", "--dpi", "150", "-p", "test"},
+			fileOutput:     "merged_output.pdf",
+			expectError:    false,
+			expectedOutput: "PDF file optimized (",
+			checkFile:      true,
+		},
+		{
+			name:           "Merge, watermark and encrypt two PDF files",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1, file2, "--watermark", "CONFIDENTIAL", "-p", "test"},
+			fileOutput:     "merged_output.pdf",
+			expectError:    false,
+			expectedOutput: "PDF files merged and encrypted successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Missing watermark image",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1, file2, "--watermark-image", "logo.png"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitIO,
+			expectedOutput: "no such file or directory",
+			checkFile:      false,
+		},
+		{
+			name:           "DPI without optimize",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1, file2, "--dpi", "150"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "the --dpi flag needs the --optimize flag",
+			checkFile:      false,
+		},
+		{
+			name:           "Refuse to overwrite an existing merged PDF file",
+			pdfs:           []string{file1, file2, "merged_output.pdf"},
+			flags:          []string{merge, file1, file2},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "merged_output.pdf already exists, use --force to overwrite it",
+			checkFile:      false,
+		},
+		{
+			name:           "Overwrite an existing merged PDF file with force",
+			pdfs:           []string{file1, file2, "merged_output.pdf"},
+			flags:          []string{merge, file1, file2, "-f"},
+			fileOutput:     "merged_output.pdf",
+			expectError:    false,
+			expectedOutput: "PDF files merged successfully to:",
+			checkFile:      true,
+		},
+		{
+			name:           "Permissions without a password",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1, file2, "--permissions", "print"},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "the --permissions flag needs a password",
+			checkFile:      false,
+		},
+		{
+			name:           "Page selection out of bounds",
+			pdfs:           []string{file1, file2},
+			flags:          []string{merge, file1 + "[2]", file2},
+			fileOutput:     "",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "out of bounds",
+			checkFile:      false,
+		},
 
 		{
-			name:           "Check if file and directory is provided",
+			name:           "Check if a directory without PDF files is provided",
 			pdfs:           []string{file1},
 			flags:          []string{merge, file1, "subdir"},
 			fileOutput:     "",
-			expectError:    false,
-			expectedOutput: "is a directory not a pdf",
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "no PDF files found in subdir",
 			checkFile:      false,
 		},
 		{
@@ -106,7 +249,8 @@ func TestMergeCommand(t *testing.T) {
 			pdfs:           nil,
 			flags:          []string{merge, "tempDir"},
 			fileOutput:     "",
-			expectError:    false,
+			expectError:    true,
+			exitCode:       exitIO,
 			expectedOutput: "no such file or directory",
 			checkFile:      false,
 		},
@@ -115,7 +259,8 @@ func TestMergeCommand(t *testing.T) {
 			pdfs:           nil,
 			flags:          []string{merge, file1, file2},
 			fileOutput:     "",
-			expectError:    false,
+			expectError:    true,
+			exitCode:       exitIO,
 			expectedOutput: "no such file or directory",
 			checkFile:      false,
 		},
@@ -124,7 +269,8 @@ func TestMergeCommand(t *testing.T) {
 			pdfs:           []string{file1, file2},
 			flags:          []string{merge, file1, file2, "-ep", "test"},
 			fileOutput:     "",
-			expectError:    false,
+			expectError:    true,
+			exitCode:       exitUsage,
 			expectedOutput: "provide either the --password flag or use the --encrypt flag",
 			checkFile:      false,
 		},
@@ -132,6 +278,7 @@ func TestMergeCommand(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t, mergeCmd)
 			tempDir := t.TempDir()
 			err := os.Chdir(tempDir)
 			assert.NoError(t, err, "failed to change directory: ", tempDir)
@@ -148,12 +295,12 @@ func TestMergeCommand(t *testing.T) {
 			err = rootCmd.Execute()
 
 			if tt.expectError {
-				assert.Error(t, err, "Expected an error but command ran successfuly.")
+				assert.ErrorContains(t, err, tt.expectedOutput, "Expected an error but command ran successfuly.")
 			} else {
 				assert.NoError(t, err, "Expected command to run successfuly but it failed.")
+				assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Unexpected output from command.")
 			}
-
-			assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Unexpected output from command.")
+			assert.Equal(t, tt.exitCode, exitCodeFor(err), "Unexpected exit code.")
 			// fmt.Println(outputBuf.String())
 
 			if tt.checkFile {
@@ -0,0 +1,47 @@
+/*
+Copyright © 2025 Aito Nakajima
+*/
+package cmd
+
+import (
+	"runtime"
+
+	"github.com/gmskazi/pdfmc/cmd/autocomplete"
+	"github.com/gmskazi/pdfmc/cmd/program"
+	"github.com/spf13/cobra"
+)
+
+var optimizeCmd = &cobra.Command{
+	Use:   "optimize [files... or folder]",
+	Short: "Make PDF files smaller.",
+	Long: `This is a tool to make PDF files smaller, fonts and images that are embedded
+more than once are kept once, unused objects are removed, the streams are
+compressed and the images can be downsampled. The size before and after is
+shown for every file.`,
+	Aliases: []string{"compress"},
+	Args:    cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		p := program.NewProgram(cmd, args, optimize)
+		return p.ExecuteOptimize()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(optimizeCmd)
+
+	optimizeCmd.Flags().Int("dpi", 0, dpiUsage)
+	optimizeCmd.Flags().StringP("name", "n", "", "Add a prefix to the beginning of the file name.")
+	optimizeCmd.Flags().Bool("recursive", false, "Search the directories for PDF files recursively.")
+	optimizeCmd.Flags().StringSlice("include", nil, "Only use the PDF files in the directories that match one of these globs.")
+	optimizeCmd.Flags().StringSlice("exclude", nil, "Skip the PDF files in the directories that match one of these globs.")
+	optimizeCmd.Flags().String("output-dir", "", "Directory to write the PDF files to, it's created when it doesn't exist.")
+	optimizeCmd.Flags().String("name-template", "", nameTemplateUsage)
+	optimizeCmd.Flags().BoolP("force", "f", false, "Overwrite files that already exist.")
+	optimizeCmd.Flags().Bool("backup", false, "Keep a copy of every file that is overwritten, restore it with the undo command.")
+	optimizeCmd.Flags().Bool("in-place", false, "Overwrite the original PDF files when there is no prefix or output directory.")
+	optimizeCmd.Flags().BoolP("keep-going", "k", false, "Keep going when a file fails and print a summary at the end.")
+	optimizeCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of PDF files to process at the same time.")
+
+	optimizeCmd.ValidArgsFunction = autocomplete.GetSuggestions
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Only testing non interactive mode for now
+func TestOptimizeCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		pdfs           []string
+		flags          []string
+		fileOutputs    []string
+		expectError    bool
+		exitCode       int
+		expectedOutput string
+		encrypted      bool
+	}{
+		{
+			name:           "Optimize a PDF file in place",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{optimize, "file1.pdf", "--in-place"},
+			fileOutputs:    []string{"file1.pdf"},
+			expectedOutput: "PDF file optimized successfully to:",
+		},
+		{
+			name:           "Optimize several files with a prefix and downsampling",
+			pdfs:           []string{"file1.pdf", "file2.pdf"},
+			flags:          []string{optimize, "file1.pdf", "file2.pdf", "--dpi", "150", "-n", "small-"},
+			fileOutputs:    []string{"small-file1.pdf", "small-file2.pdf"},
+			expectedOutput: " → ",
+		},
+		{
+			name:           "Compress is an alias",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{"compress", "file1.pdf", "--output-dir", "out", "-k"},
+			fileOutputs:    []string{"out/file1.pdf"},
+			expectedOutput: "1 succeeded, 0 failed",
+		},
+		{
+			name:           "Negative DPI",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{optimize, "file1.pdf", "--dpi", "-1", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "the --dpi flag can't be negative",
+		},
+		{
+			name:           "Original is not overwritten without in place",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{optimize, "file1.pdf"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "use --in-place to overwrite the original PDF files",
+		},
+		{
+			name:           "Encrypted PDF file",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{optimize, "file1.pdf", "--in-place"},
+			expectError:    true,
+			exitCode:       exitInvalidPDF,
+			expectedOutput: "file1.pdf is encrypted, decrypt it first",
+			encrypted:      true,
+		},
+		{
+			name:           "Check if files are available",
+			pdfs:           nil,
+			flags:          []string{optimize, "file1.pdf"},
+			expectError:    true,
+			exitCode:       exitIO,
+			expectedOutput: "no such file or directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t, optimizeCmd)
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+			createTestFiles(t, tempDir, tt.pdfs)
+			if tt.encrypted {
+				encryptTestFiles(t, tempDir, tt.pdfs, "test", "")
+			}
+
+			var outputBuf bytes.Buffer
+
+			rootCmd.SetOut(&outputBuf)
+			rootCmd.SetErr(&outputBuf)
+			rootCmd.SetArgs(tt.flags)
+
+			err = rootCmd.Execute()
+
+			if tt.expectError {
+				assert.ErrorContains(t, err, tt.expectedOutput, "Expected an error but command ran successfully.")
+			} else {
+				assert.NoError(t, err, "Expected command to run successfully but it failed.")
+				assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Expected output to contain: %s", tt.expectedOutput)
+			}
+			assert.Equal(t, tt.exitCode, exitCodeFor(err), "Unexpected exit code.")
+
+			for _, f := range tt.fileOutputs {
+				_, err := os.Stat(f)
+				assert.NoError(t, err, "Expected file %s to be created but it was not found.", f)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/hex"
+	"image"
+	_ "image/jpeg" // decodes the JPEG images of scanned pages
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// PageText is a best effort, text in fonts with their own encoding comes out garbled.
+func PageText(pdf string, page int) (string, error) {
+	f, err := os.Open(filepath.Clean(pdf))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	ctx, err := api.ReadAndValidate(f, model.NewDefaultConfiguration())
+	if err != nil {
+		return "", invalidPDF(err)
+	}
+
+	r, err := pdfcpu.ExtractPageContent(ctx, page)
+	if err != nil {
+		return "", invalidPDF(err)
+	}
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return contentText(content), nil
+}
+
+// PageImage returns the largest image on a page, or nil when none can be decoded.
+func PageImage(pdf string, page int) (image.Image, error) {
+	f, err := os.Open(filepath.Clean(pdf))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	pages, err := api.ExtractImagesRaw(f, []string{strconv.Itoa(page)}, nil)
+	if err != nil {
+		return nil, invalidPDF(err)
+	}
+
+	var largest image.Image
+	for _, images := range pages {
+		for _, img := range images {
+			if img.FileType != "png" && img.FileType != "jpg" {
+				continue
+			}
+			decoded, _, err := image.Decode(img)
+			if err != nil {
+				continue
+			}
+			if largest == nil || area(decoded) > area(largest) {
+				largest = decoded
+			}
+		}
+	}
+	return largest, nil
+}
+
+func area(img image.Image) int {
+	return img.Bounds().Dx() * img.Bounds().Dy()
+}
+
+func contentText(content []byte) string {
+	var (
+		lines   []string
+		line    strings.Builder
+		strs    []string
+		inArray bool
+	)
+
+	newLine := func() {
+		if text := strings.TrimSpace(line.String()); text != "" {
+			lines = append(lines, text)
+		}
+		line.Reset()
+	}
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case isSpace(c):
+			i++
+
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+
+		case c == '(':
+			var s string
+			s, i = literalString(content, i+1)
+			strs = append(strs, s)
+
+		case c == '<' && i+1 < len(content) && content[i+1] == '<':
+			i += 2
+
+		case c == '>':
+			// ">>" is skipped one byte at a time, so a stray '>' doesn't
+			// swallow the next token
+			i++
+
+		case c == '<':
+			end := bytes.IndexByte(content[i:], '>')
+			if end < 0 {
+				end = len(content) - i
+			}
+			strs = append(strs, hexString(content[i+1:i+end]))
+			i += end + 1
+
+		case c == '/':
+			// names are operands that aren't shown
+			i++
+			for i < len(content) && !isSpace(content[i]) && !isDelimiter(content[i]) {
+				i++
+			}
+
+		case c == '[' || c == ']':
+			inArray = c == '['
+			i++
+
+		default:
+			start := i
+			for i < len(content) && !isSpace(content[i]) && !isDelimiter(content[i]) {
+				i++
+			}
+			if i == start {
+				i++
+				continue
+			}
+			token := string(content[start:i])
+
+			// a big gap between the strings of a TJ array is a space
+			if n, err := strconv.ParseFloat(token, 64); err == nil {
+				if inArray && n < -200 {
+					strs = append(strs, " ")
+				}
+				continue
+			}
+
+			switch token {
+			case "Tj", "TJ":
+				line.WriteString(strings.Join(strs, ""))
+			case "'", "\"":
+				newLine()
+				line.WriteString(strings.Join(strs, ""))
+			case "Td", "TD", "T*", "Tm", "ET":
+				newLine()
+			case "ID":
+				// skip the data of inline images
+				end := bytes.Index(content[i:], []byte("EI"))
+				if end < 0 {
+					end = len(content) - i
+				}
+				i += end + 2
+			}
+			strs = nil
+		}
+	}
+	newLine()
+	return strings.Join(lines, "\n")
+}
+
+func literalString(content []byte, i int) (string, int) {
+	var s []byte
+	depth := 0
+
+	for ; i < len(content); i++ {
+		c := content[i]
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return decodeText(s), i + 1
+			}
+			depth--
+		case '\\':
+			i++
+			if i >= len(content) {
+				break
+			}
+			switch e := content[i]; e {
+			case 'n', 'r', 't', 'b', 'f':
+				s = append(s, ' ')
+			case '\r', '\n':
+				// a line continuation
+			default:
+				if e >= '0' && e <= '7' {
+					n := 0
+					for j := 0; j < 3 && i < len(content) && content[i] >= '0' && content[i] <= '7'; j++ {
+						n = n*8 + int(content[i]-'0')
+						i++
+					}
+					i--
+					s = append(s, byte(n))
+				} else {
+					s = append(s, e)
+				}
+			}
+			continue
+		}
+		s = append(s, c)
+	}
+	return decodeText(s), i
+}
+
+func hexString(content []byte) string {
+	digits := bytes.Map(func(r rune) rune {
+		if isSpace(byte(r)) {
+			return -1
+		}
+		return r
+	}, content)
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	decoded, err := hex.DecodeString(string(digits))
+	if err != nil {
+		return ""
+	}
+	return decodeText(decoded)
+}
+
+// decodeText reads the bytes as Latin-1, that's close enough to the standard
+// PDF encodings.
+func decodeText(s []byte) string {
+	var b strings.Builder
+	for _, c := range s {
+		if c >= 0x20 && c != 0x7f {
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
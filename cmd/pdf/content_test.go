@@ -0,0 +1,76 @@
+package pdf
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContentText(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "Tj strings on separate lines",
+			content:  "BT /F1 12 Tf 72 712 Td (Quarterly report) Tj 0 -14 Td (Page one) Tj ET",
+			expected: "Quarterly report\nPage one",
+		},
+		{
+			name:     "TJ array with kerning and a gap",
+			content:  "BT [(Hel) -20 (lo) -500 (world)] TJ ET",
+			expected: "Hello world",
+		},
+		{
+			name:     "Escapes and nested parentheses",
+			content:  `BT (a \(b\) \101 (c)) Tj ET`,
+			expected: "a (b) A (c)",
+		},
+		{
+			name:     "Hex strings and next line operators",
+			content:  "BT <48692>Tj (one) ' (two) ' ET",
+			expected: "Hi\none\ntwo",
+		},
+		{
+			name:     "Inline images and comments are skipped",
+			content:  "% a comment (not text)\nBI /W 1 /H 1 ID \x00(x)\xff EI BT (after) Tj ET",
+			expected: "after",
+		},
+		{
+			name:     "Dictionaries and a stray closing bracket",
+			content:  "/P <</MCID 0>> BDC BT >(after) Tj ET EMC",
+			expected: "after",
+		},
+		{
+			name:     "No text",
+			content:  "0 0 1 rg 0 0 100 100 re f",
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, contentText([]byte(tt.content)))
+		})
+	}
+}
+
+func TestPageText(t *testing.T) {
+	tempDir := t.TempDir()
+	file := filepath.Join(tempDir, "blank.pdf")
+	err := createValidPDF(file)
+	assert.NoError(t, err)
+
+	text, err := PageText(file, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, text)
+
+	_, err = PageText(file, 2)
+	assert.Error(t, err, "page out of bounds")
+
+	img, err := PageImage(file, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, img, "Expected no image on a blank page")
+}
@@ -0,0 +1,86 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+const (
+	AlgorithmAES = "aes"
+	AlgorithmRC4 = "rc4"
+)
+
+// keyLengths lists the default key length of an algorithm first.
+var keyLengths = map[string][]int{
+	AlgorithmAES: {256, 128},
+	AlgorithmRC4: {128, 40},
+}
+
+func (p *PDFProcessor) SetOwnerPassword(password string) {
+	p.ownerPassword = password
+}
+
+func (p *PDFProcessor) SetPermissions(permissions model.PermissionFlags) {
+	p.permissions = permissions
+}
+
+// SetAlgorithm picks the strongest key length of the algorithm when keyLength is 0.
+func (p *PDFProcessor) SetAlgorithm(algorithm string, keyLength int) error {
+	algorithm = strings.ToLower(algorithm)
+
+	lengths, ok := keyLengths[algorithm]
+	if !ok {
+		return fmt.Errorf("unknown encryption algorithm %q, valid algorithms are: %s, %s", algorithm, AlgorithmAES, AlgorithmRC4)
+	}
+
+	if keyLength == 0 {
+		keyLength = lengths[0]
+	}
+
+	for _, length := range lengths {
+		if length == keyLength {
+			p.algorithm = algorithm
+			p.keyLength = keyLength
+			return nil
+		}
+	}
+	return fmt.Errorf("%s encryption doesn't support a key length of %d, valid key lengths are: %s", algorithm, keyLength, strings.Trim(fmt.Sprint(lengths), "[]"))
+}
+
+func (p *PDFProcessor) encryptionConfig(password string) *model.Configuration {
+	var conf *model.Configuration
+
+	ownerPassword := p.ownerPassword
+	if ownerPassword == "" {
+		ownerPassword = password
+	}
+
+	switch p.algorithm {
+	case AlgorithmRC4:
+		conf = model.NewRC4Configuration(password, ownerPassword, p.keyLength)
+	case AlgorithmAES:
+		conf = model.NewAESConfiguration(password, ownerPassword, p.keyLength)
+	default:
+		conf = model.NewAESConfiguration(password, ownerPassword, 256)
+	}
+
+	if p.permissions != 0 {
+		conf.Permissions = p.permissions
+	}
+	return conf
+}
+
+func decryptionConfig(password string) *model.Configuration {
+	conf := model.NewDefaultConfiguration()
+	conf.UserPW = password
+	conf.OwnerPW = password
+	return conf
+}
+
+func IsWrongPassword(err error) bool {
+	return errors.Is(err, pdfcpu.ErrWrongPassword)
+}
@@ -0,0 +1,113 @@
+package pdf
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAlgorithm(t *testing.T) {
+	tests := []struct {
+		name              string
+		algorithm         string
+		keyLength         int
+		expectedAlgorithm string
+		expectedKeyLength int
+		expectedErr       bool
+	}{
+		{
+			name:              "aes default key length",
+			algorithm:         "aes",
+			keyLength:         0,
+			expectedAlgorithm: AlgorithmAES,
+			expectedKeyLength: 256,
+		},
+		{
+			name:              "aes 128",
+			algorithm:         "AES",
+			keyLength:         128,
+			expectedAlgorithm: AlgorithmAES,
+			expectedKeyLength: 128,
+		},
+		{
+			name:              "rc4 default key length",
+			algorithm:         "rc4",
+			keyLength:         0,
+			expectedAlgorithm: AlgorithmRC4,
+			expectedKeyLength: 128,
+		},
+		{
+			name:              "rc4 40",
+			algorithm:         "rc4",
+			keyLength:         40,
+			expectedAlgorithm: AlgorithmRC4,
+			expectedKeyLength: 40,
+		},
+		{
+			name:        "rc4 256",
+			algorithm:   "rc4",
+			keyLength:   256,
+			expectedErr: true,
+		},
+		{
+			name:        "aes 40",
+			algorithm:   "aes",
+			keyLength:   40,
+			expectedErr: true,
+		},
+		{
+			name:        "unknown algorithm",
+			algorithm:   "des",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := NewPDFProcessor(encrypt).InPlace()
+			err := processor.SetAlgorithm(tt.algorithm, tt.keyLength)
+			if tt.expectedErr {
+				assert.Error(t, err, "Expected an error but the algorithm was accepted")
+			} else {
+				assert.NoError(t, err, "Expected the algorithm to be accepted")
+			}
+
+			assert.Equal(t, tt.expectedAlgorithm, processor.algorithm)
+			assert.Equal(t, tt.expectedKeyLength, processor.keyLength)
+		})
+	}
+}
+
+func TestEncryptDecryptAlgorithms(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		keyLength int
+	}{
+		{name: "aes 256", algorithm: AlgorithmAES, keyLength: 256},
+		{name: "aes 128", algorithm: AlgorithmAES, keyLength: 128},
+		{name: "rc4 128", algorithm: AlgorithmRC4, keyLength: 128},
+		{name: "rc4 40", algorithm: AlgorithmRC4, keyLength: 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: %s", tempDir)
+			createTestFiles(t, tempDir, []string{"test.pdf"})
+
+			encryptor := NewPDFProcessor(encrypt).InPlace()
+			err = encryptor.SetAlgorithm(tt.algorithm, tt.keyLength)
+			assert.NoError(t, err)
+
+			_, err = encryptor.EncryptPdf("test.pdf", tempDir, "test", "")
+			assert.NoError(t, err, "Expected the PDF to be encrypted")
+
+			// decryption picks up the algorithm from the PDF itself
+			_, err = NewPDFProcessor(decrypt).InPlace().DecryptPdf("test.pdf", tempDir, "test", "")
+			assert.NoError(t, err, "Expected the PDF to be decrypted")
+		})
+	}
+}
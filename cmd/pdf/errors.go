@@ -0,0 +1,45 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+var ErrInvalidPDF = errors.New("invalid PDF")
+
+type invalidPDFError struct {
+	err error
+}
+
+func (e *invalidPDFError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidPDFError) Unwrap() error {
+	return e.err
+}
+
+func (e *invalidPDFError) Is(target error) bool {
+	return target == ErrInvalidPDF
+}
+
+// invalidPDF returns wrong passwords and I/O errors as they are.
+func invalidPDF(err error) error {
+	var pathErr *fs.PathError
+	if err == nil || IsWrongPassword(err) || errors.As(err, &pathErr) {
+		return err
+	}
+	return &invalidPDFError{err: err}
+}
+
+func checkNoPassword(input string, content []byte) error {
+	if _, err := api.PageCount(bytes.NewReader(content), nil); IsWrongPassword(err) {
+		return &invalidPDFError{err: fmt.Errorf("%s is encrypted, decrypt it first", filepath.Base(input))}
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package pdf
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+type Metadata struct {
+	Pages     int
+	Version   string
+	Encrypted bool
+	// the page count isn't known for a locked PDF
+	Locked bool
+}
+
+var headerVersion = regexp.MustCompile(`%PDF-(\d\.\d)`)
+
+func ReadMetadata(pdf string) (Metadata, error) {
+	var meta Metadata
+
+	f, err := os.Open(filepath.Clean(pdf))
+	if err != nil {
+		return meta, err
+	}
+	defer f.Close()
+
+	ctx, err := api.ReadAndValidate(f, model.NewDefaultConfiguration())
+	if IsWrongPassword(err) {
+		meta.Encrypted = true
+		meta.Locked = true
+		if _, err := f.Seek(0, 0); err != nil {
+			return meta, err
+		}
+		// the version in the header can be read without the password
+		header, _ := bufio.NewReader(f).Peek(16)
+		if match := headerVersion.FindSubmatch(header); match != nil {
+			meta.Version = string(match[1])
+		}
+		return meta, nil
+	}
+	if err != nil {
+		return meta, invalidPDF(err)
+	}
+
+	meta.Pages = ctx.PageCount
+	meta.Version = ctx.VersionString()
+	meta.Encrypted = ctx.Encrypt != nil
+	return meta, nil
+}
+
+func Title(pdf string) (string, error) {
+	f, err := os.Open(filepath.Clean(pdf))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := api.PDFInfo(f, pdf, nil, nil)
+	if err != nil {
+		return "", invalidPDF(err)
+	}
+	return info.Title, nil
+}
@@ -0,0 +1,78 @@
+package pdf
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTitle(t *testing.T) {
+	tempDir := t.TempDir()
+
+	untitled := filepath.Join(tempDir, "untitled.pdf")
+	err := createValidPDF(untitled)
+	assert.NoError(t, err)
+
+	titled := filepath.Join(tempDir, "titled.pdf")
+	err = api.AddPropertiesFile(untitled, titled, map[string]string{"Title": "Quarterly report"}, nil)
+	assert.NoError(t, err)
+
+	title, err := Title(titled)
+	assert.NoError(t, err)
+	assert.Equal(t, "Quarterly report", title)
+
+	title, err = Title(untitled)
+	assert.NoError(t, err)
+	assert.Empty(t, title)
+
+	_, err = Title(filepath.Join(tempDir, "missing.pdf"))
+	assert.Error(t, err)
+}
+
+func TestReadMetadata(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestFiles(t, tempDir, []string{"plain.pdf", "locked.pdf", "owner.pdf"})
+
+	_, err := NewPDFProcessor(encrypt).InPlace().EncryptPdf("locked.pdf", tempDir, "test", "")
+	assert.NoError(t, err)
+
+	processor := NewPDFProcessor(encrypt).InPlace()
+	processor.SetOwnerPassword("owner")
+	_, err = processor.EncryptPdf("owner.pdf", tempDir, "", "")
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		pdf      string
+		expected Metadata
+	}{
+		{
+			name:     "plain PDF",
+			pdf:      "plain.pdf",
+			expected: Metadata{Pages: 1, Version: "1.4"},
+		},
+		{
+			name:     "PDF with a user password",
+			pdf:      "locked.pdf",
+			expected: Metadata{Version: "1.7", Encrypted: true, Locked: true},
+		},
+		{
+			name:     "PDF with only an owner password",
+			pdf:      "owner.pdf",
+			expected: Metadata{Pages: 1, Version: "1.7", Encrypted: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			meta, err := ReadMetadata(filepath.Join(tempDir, tt.pdf))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, meta)
+		})
+	}
+
+	_, err = ReadMetadata(filepath.Join(tempDir, "missing.pdf"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,241 @@
+package pdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+const jpegQuality = 80
+
+type Optimization struct {
+	// 0 keeps the images as they are
+	DPI int
+}
+
+func (p *PDFProcessor) OptimizePdf(pdf, dir, prefix string, optimization Optimization) (string, error) {
+	p = p.inSubdir(dir, pdf)
+	input := filepath.Join(dir, pdf)
+
+	content, err := os.ReadFile(filepath.Clean(input))
+	if err != nil {
+		return "", err
+	}
+	if err := checkNoPassword(input, content); err != nil {
+		return "", err
+	}
+
+	output, err := p.outputPath(input, prefix, "", allPages(func() (int, error) {
+		return api.PageCount(bytes.NewReader(content), nil)
+	}))
+	if err != nil {
+		return "", invalidPDF(err)
+	}
+
+	err = p.writeFile(input, output, nil, func(w io.Writer) error {
+		return writeOptimized(content, w, optimization)
+	})
+	if err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+func writeOptimized(content []byte, w io.Writer, optimization Optimization) error {
+	conf := model.NewDefaultConfiguration()
+	conf.WriteObjectStream = true
+	conf.WriteXRefStream = true
+
+	// reading optimizes the PDF, that's where the duplicates are removed
+	ctx, err := api.ReadValidateAndOptimize(bytes.NewReader(content), conf)
+	if err != nil {
+		return invalidPDF(err)
+	}
+
+	if optimization.DPI > 0 {
+		if err := downsampleImages(ctx, optimization.DPI); err != nil {
+			return invalidPDF(err)
+		}
+	}
+	if err := compressStreams(ctx); err != nil {
+		return invalidPDF(err)
+	}
+
+	// only the objects that are reachable from the document are written
+	return invalidPDF(api.WriteContext(ctx, w))
+}
+
+func compressStreams(ctx *model.Context) error {
+	for _, entry := range ctx.Table {
+		if entry == nil || entry.Free {
+			continue
+		}
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok || sd.FilterPipeline != nil || sd.Raw == nil {
+			continue
+		}
+		if t := sd.Type(); t != nil && (*t == "Metadata" || *t == "XRef" || *t == "ObjStm") {
+			continue
+		}
+
+		if err := sd.Decode(); err != nil {
+			return err
+		}
+		sd.FilterPipeline = []types.PDFFilter{{Name: filter.Flate}}
+		sd.InsertName("Filter", filter.Flate)
+		if err := sd.Encode(); err != nil {
+			return err
+		}
+		entry.Object = sd
+	}
+	return nil
+}
+
+func downsampleImages(ctx *model.Context, dpi int) error {
+	var side float64
+	for page := 1; page <= ctx.PageCount; page++ {
+		_, _, attrs, err := ctx.PageDict(page, false)
+		if err != nil {
+			return err
+		}
+		if attrs.MediaBox != nil {
+			side = math.Max(side, math.Max(attrs.MediaBox.Width(), attrs.MediaBox.Height()))
+		}
+	}
+	// the longest side of an image never needs more pixels than the longest
+	// side of the page
+	limit := side / 72 * float64(dpi)
+
+	images := make(map[int]types.StreamDict)
+	masks := make(map[int]bool)
+	for objNr, entry := range ctx.Table {
+		if entry == nil || entry.Free {
+			continue
+		}
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok || sd.Subtype() == nil || *sd.Subtype() != "Image" {
+			continue
+		}
+		images[objNr] = sd
+		for _, key := range []string{"SMask", "Mask"} {
+			if ref := sd.IndirectRefEntry(key); ref != nil {
+				masks[ref.ObjectNumber.Value()] = true
+			}
+		}
+	}
+
+	for objNr, sd := range images {
+		width, height := sd.IntEntry("Width"), sd.IntEntry("Height")
+		if masks[objNr] || width == nil || height == nil {
+			continue
+		}
+		// other color spaces and /Decode arrays don't survive being decoded
+		if cs := sd.NameEntry("ColorSpace"); cs == nil || *cs != model.DeviceRGBCS && *cs != model.DeviceGrayCS {
+			continue
+		}
+		if sd.ArrayEntry("Decode") != nil {
+			continue
+		}
+
+		scale := limit / float64(max(*width, *height))
+		if scale >= 1 {
+			continue
+		}
+
+		img, err := pdfcpu.ExtractImage(ctx, &sd, false, "", objNr, false)
+		if err != nil {
+			return err
+		}
+		if img == nil || img.IsImgMask || img.HasImgMask || img.HasSMask {
+			continue
+		}
+		if err := replaceImage(ctx, objNr, *img, *width, *height, scale); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func replaceImage(ctx *model.Context, objNr int, img model.Image, width, height int, scale float64) error {
+	if img.FileType != "jpg" && img.FileType != "png" {
+		return nil
+	}
+	decoded, _, err := image.Decode(img)
+	if err != nil {
+		// images Go can't decode are kept
+		return nil
+	}
+
+	width = max(int(float64(width)*scale), 1)
+	height = max(int(float64(height)*scale), 1)
+	scaled := resize(decoded, width, height)
+
+	var encoded bytes.Buffer
+	if img.FileType == "jpg" {
+		err = jpeg.Encode(&encoded, scaled, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		err = png.Encode(&encoded, scaled)
+	}
+	if err != nil {
+		return err
+	}
+
+	sd, _, _, err := model.CreateImageStreamDict(ctx.XRefTable, &encoded, false, false)
+	if err != nil {
+		return err
+	}
+
+	entry, ok := ctx.FindTableEntryLight(objNr)
+	if !ok {
+		return nil
+	}
+	old, ok := entry.Object.(types.StreamDict)
+	if !ok {
+		return nil
+	}
+	if old.StreamLength != nil && int64(len(sd.Raw)) >= *old.StreamLength {
+		return nil
+	}
+	if interpolate, ok := old.Find("Interpolate"); ok {
+		sd.Insert("Interpolate", interpolate)
+	}
+	entry.Object = *sd
+	return nil
+}
+
+func resize(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := max(bounds.Min.Y+(y+1)*bounds.Dy()/height, y0+1)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := max(bounds.Min.X+(x+1)*bounds.Dx()/width, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := img.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
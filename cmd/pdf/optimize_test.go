@@ -0,0 +1,182 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// createUncompressedPDF creates a PDF with a large content stream that
+// isn't compressed.
+func createUncompressedPDF(t *testing.T, file string) {
+	stream := strings.Repeat("BT /F1 12 Tf 72 712 Td (Hello, World!) Tj ET\n", 500)
+	writeObjects(t, file, []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(stream), stream),
+	})
+}
+
+// createIndexedImagePDF creates a PDF with an image of width by height
+// pixels in an Indexed color space, the page is as large as the image.
+func createIndexedImagePDF(t *testing.T, file string, width, height int) {
+	pixels := make([]byte, width*height)
+	for i := range pixels {
+		pixels[i] = byte(i / width % 2)
+	}
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	_, err := zw.Write(pixels)
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	content := fmt.Sprintf("q %d 0 0 %d 0 0 cm /Im1 Do Q", width, height)
+	writeObjects(t, file, []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /XObject << /Im1 5 0 R >> >> /Contents 4 0 R >>", width, height),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+		fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace [/Indexed /DeviceRGB 1 <0000FFFF0000>] /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n%s\nendstream", width, height, compressed.Len(), compressed.String()),
+	})
+}
+
+func writeObjects(t *testing.T, file string, objects []string) {
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Root 1 0 R /Size %d >>\nstartxref\n%d\n%%%%EOF", len(objects)+1, xref)
+
+	err := os.WriteFile(file, b.Bytes(), 0644)
+	assert.NoError(t, err, "failed to create test file: ", file)
+}
+
+// createImagePDF creates a PDF with a JPEG image of width by height pixels
+// on its only page, the page is as large as the image in points.
+func createImagePDF(t *testing.T, file string, width, height int) {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x ^ y), A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	var out bytes.Buffer
+	err := api.ImportImages(nil, &out, []io.Reader{&encoded}, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(file, out.Bytes(), 0644))
+}
+
+// imageWidths returns the width of every image in a PDF.
+func imageWidths(t *testing.T, file string) []int {
+	ctx, err := api.ReadContextFile(file)
+	assert.NoError(t, err)
+
+	var widths []int
+	for _, entry := range ctx.Table {
+		sd, ok := entry.Object.(types.StreamDict)
+		if !ok || sd.Subtype() == nil || *sd.Subtype() != "Image" {
+			continue
+		}
+		width := sd.IntEntry("Width")
+		assert.NotNil(t, width)
+		widths = append(widths, *width)
+	}
+	return widths
+}
+
+func fileSize(t *testing.T, file string) int64 {
+	info, err := os.Stat(file)
+	assert.NoError(t, err)
+	return info.Size()
+}
+
+func TestOptimizePdf(t *testing.T) {
+	t.Run("Streams are compressed", func(t *testing.T) {
+		tempDir := t.TempDir()
+		err := os.Chdir(tempDir)
+		assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+		createUncompressedPDF(t, "text.pdf")
+
+		output, err := NewPDFProcessor(optimize).OptimizePdf("text.pdf", tempDir, "optimized-", Optimization{})
+		assert.NoError(t, err)
+		assert.Equal(t, "optimized-text.pdf", output)
+		assert.Less(t, fileSize(t, output), fileSize(t, "text.pdf")/2)
+
+		text, err := PageText(output, 1)
+		assert.NoError(t, err)
+		assert.Contains(t, text, "Hello, World!")
+	})
+
+	t.Run("Images are downsampled", func(t *testing.T) {
+		tempDir := t.TempDir()
+		err := os.Chdir(tempDir)
+		assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+		createImagePDF(t, "image.pdf", 1600, 1200)
+
+		// the page is 1600 points wide, that's 800 pixels at 36 DPI
+		output, err := NewPDFProcessor(optimize).OptimizePdf("image.pdf", tempDir, "optimized-", Optimization{DPI: 36})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{800}, imageWidths(t, output))
+		assert.Less(t, fileSize(t, output), fileSize(t, "image.pdf"))
+	})
+
+	t.Run("Indexed images are kept", func(t *testing.T) {
+		tempDir := t.TempDir()
+		err := os.Chdir(tempDir)
+		assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+		createIndexedImagePDF(t, "indexed.pdf", 1600, 1200)
+
+		output, err := NewPDFProcessor(optimize).OptimizePdf("indexed.pdf", tempDir, "optimized-", Optimization{DPI: 36})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1600}, imageWidths(t, output))
+	})
+
+	t.Run("Images that are small enough are kept", func(t *testing.T) {
+		tempDir := t.TempDir()
+		err := os.Chdir(tempDir)
+		assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+		createImagePDF(t, "image.pdf", 400, 300)
+
+		output, err := NewPDFProcessor(optimize).OptimizePdf("image.pdf", tempDir, "optimized-", Optimization{DPI: 72})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{400}, imageWidths(t, output))
+	})
+
+	t.Run("Overwriting the original needs in place", func(t *testing.T) {
+		tempDir := t.TempDir()
+		err := os.Chdir(tempDir)
+		assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+		createUncompressedPDF(t, "text.pdf")
+
+		_, err = NewPDFProcessor(optimize).OptimizePdf("text.pdf", tempDir, "", Optimization{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,120 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var NameTokens = []string{"{stem}", "{date}", "{index}", "{op}", "{pages}"}
+
+var nameTokenPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+func CheckNameTemplate(nameTemplate string) error {
+	for _, token := range nameTokenPattern.FindAllString(nameTemplate, -1) {
+		if !slices.Contains(NameTokens, token) {
+			return fmt.Errorf("unknown name template token %s, valid tokens are: %s", token, strings.Join(NameTokens, ", "))
+		}
+	}
+	return nil
+}
+
+func (p *PDFProcessor) SetOutput(dir, nameTemplate string) error {
+	if err := CheckNameTemplate(nameTemplate); err != nil {
+		return err
+	}
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return err
+		}
+	}
+
+	p.outputDir = dir
+	p.nameTemplate = nameTemplate
+	return nil
+}
+
+// WithIndex returns a copy of the processor for the index-th file, starting at 1.
+func (p *PDFProcessor) WithIndex(index int) *PDFProcessor {
+	c := *p
+	c.index = index
+	return &c
+}
+
+func (p *PDFProcessor) InPlace() *PDFProcessor {
+	c := *p
+	c.outputDir = ""
+	c.nameTemplate = ""
+	c.inPlace = true
+	return &c
+}
+
+// inSubdir keeps the subfolder pdf is in below dir in the output directory.
+func (p *PDFProcessor) inSubdir(dir, pdf string) *PDFProcessor {
+	c := *p
+	if subdir := filepath.Dir(pdf); dir != "" && filepath.IsLocal(pdf) && subdir != "." {
+		c.subdir = subdir
+	}
+	return &c
+}
+
+// outputPath keeps the name of input for an empty name, pages is only called
+// for the {pages} token.
+func (p *PDFProcessor) outputPath(input, prefix, name string, pages func() (string, error)) (string, error) {
+	if p.nameTemplate != "" {
+		expanded, err := p.expandNameTemplate(input, pages)
+		if err != nil {
+			return "", err
+		}
+		name = expanded
+	}
+
+	if name == "" {
+		if prefix == "" && p.outputDir == "" {
+			return input, nil
+		}
+		name = filepath.Base(input)
+	}
+	return filepath.Join(p.outputDir, p.subdir, prefix+name), nil
+}
+
+func (p *PDFProcessor) expandNameTemplate(input string, pages func() (string, error)) (string, error) {
+	var pageRange string
+
+	if strings.Contains(p.nameTemplate, "{pages}") {
+		var err error
+		pageRange, err = pages()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	replacer := strings.NewReplacer(
+		"{stem}", stem(input),
+		"{date}", time.Now().Format(time.DateOnly),
+		"{index}", strconv.Itoa(max(p.index, 1)),
+		"{op}", p.logo,
+		"{pages}", pageRange,
+	)
+	return p.pdfExtension(replacer.Replace(p.nameTemplate)), nil
+}
+
+func allPages(pageCount func() (int, error)) func() (string, error) {
+	return func() (string, error) {
+		count, err := pageCount()
+		if err != nil {
+			return "", err
+		}
+		return PageRange{From: 1, Thru: count}.String(), nil
+	}
+}
+
+func stem(pdf string) string {
+	return strings.TrimSuffix(filepath.Base(pdf), filepath.Ext(pdf))
+}
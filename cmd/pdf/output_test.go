@@ -0,0 +1,100 @@
+package pdf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutputPath(t *testing.T) {
+	date := time.Now().Format(time.DateOnly)
+	pages := func() (string, error) { return "1-3", nil }
+
+	tests := []struct {
+		name         string
+		outputDir    string
+		nameTemplate string
+		index        int
+		dir          string
+		input        string
+		prefix       string
+		defaultName  string
+		expected     string
+	}{
+		{
+			name:     "overwrite the input",
+			input:    filepath.Join("docs", "report.pdf"),
+			expected: filepath.Join("docs", "report.pdf"),
+		},
+		{
+			name:     "prefix",
+			input:    filepath.Join("docs", "report.pdf"),
+			prefix:   "encrypted-",
+			expected: "encrypted-report.pdf",
+		},
+		{
+			name:      "output directory",
+			outputDir: "out",
+			input:     filepath.Join("docs", "report.pdf"),
+			expected:  filepath.Join("out", "report.pdf"),
+		},
+		{
+			name:        "default name in the output directory",
+			outputDir:   "out",
+			input:       "report.pdf",
+			prefix:      "part-",
+			defaultName: "report_1-3.pdf",
+			expected:    filepath.Join("out", "part-report_1-3.pdf"),
+		},
+		{
+			name:      "subfolder of a directory",
+			outputDir: "out",
+			dir:       "docs",
+			input:     filepath.Join("2024", "report.pdf"),
+			expected:  filepath.Join("out", "2024", "report.pdf"),
+		},
+		{
+			name:     "subfolder of a directory in place",
+			dir:      "docs",
+			input:    filepath.Join("2024", "report.pdf"),
+			expected: filepath.Join("docs", "2024", "report.pdf"),
+		},
+		{
+			name:         "name template",
+			outputDir:    "out",
+			nameTemplate: "{op}-{index}-{stem}-{pages}-{date}",
+			index:        2,
+			input:        filepath.Join("docs", "report.pdf"),
+			expected:     filepath.Join("out", "encrypt-2-report-1-3-"+date+".pdf"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := os.Chdir(t.TempDir())
+			assert.NoError(t, err, "failed to change directory")
+
+			processor := NewPDFProcessor(encrypt).InPlace()
+			err = processor.SetOutput(tt.outputDir, tt.nameTemplate)
+			assert.NoError(t, err)
+
+			input := filepath.Join(tt.dir, tt.input)
+			output, err := processor.WithIndex(tt.index).inSubdir(tt.dir, tt.input).outputPath(input, tt.prefix, tt.defaultName, pages)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, output)
+
+			if tt.outputDir != "" {
+				assert.DirExists(t, tt.outputDir, "Expected the output directory to be created")
+			}
+		})
+	}
+}
+
+func TestCheckNameTemplate(t *testing.T) {
+	assert.NoError(t, CheckNameTemplate("{stem}_{date}"))
+	assert.NoError(t, CheckNameTemplate(""))
+	assert.ErrorContains(t, CheckNameTemplate("{stem}_{time}"), "unknown name template token {time}")
+}
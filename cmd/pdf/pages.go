@@ -0,0 +1,91 @@
+package pdf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type PageRange struct {
+	From int
+	Thru int
+}
+
+func (r PageRange) String() string {
+	if r.From == r.Thru {
+		return strconv.Itoa(r.From)
+	}
+	return fmt.Sprintf("%d-%d", r.From, r.Thru)
+}
+
+type PageRangeError struct {
+	Err error
+}
+
+func (e *PageRangeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PageRangeError) Unwrap() error {
+	return e.Err
+}
+
+func pageRangeError(format string, a ...any) error {
+	return &PageRangeError{Err: fmt.Errorf(format, a...)}
+}
+
+func ParsePageRanges(spec string, pageCount int) ([]PageRange, error) {
+	var ranges []PageRange
+
+	if strings.TrimSpace(spec) == "" {
+		return nil, pageRangeError("no page ranges provided")
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, pageRangeError("invalid page range in %q", spec)
+		}
+
+		var (
+			r   PageRange
+			err error
+		)
+
+		from, thru, isRange := strings.Cut(part, "-")
+		r.From, err = parsePageNumber(from, 1)
+		if err != nil {
+			return nil, pageRangeError("invalid page range %q: %w", part, err)
+		}
+
+		switch {
+		case !isRange:
+			r.Thru = r.From
+		case strings.TrimSpace(thru) == "":
+			r.Thru = pageCount
+		default:
+			r.Thru, err = parsePageNumber(thru, pageCount)
+			if err != nil {
+				return nil, pageRangeError("invalid page range %q: %w", part, err)
+			}
+		}
+
+		if r.From < 1 || r.Thru > pageCount {
+			return nil, pageRangeError("page range %q is out of bounds, the document has %d pages", part, pageCount)
+		}
+		if r.From > r.Thru {
+			return nil, pageRangeError("page range %q starts after it ends", part)
+		}
+
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+func parsePageNumber(s string, fallback int) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(s)
+}
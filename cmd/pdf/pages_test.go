@@ -0,0 +1,84 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePageRanges(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		pageCount   int
+		expected    []PageRange
+		expectedErr bool
+	}{
+		{
+			name:      "single page",
+			spec:      "3",
+			pageCount: 5,
+			expected:  []PageRange{{From: 3, Thru: 3}},
+		},
+		{
+			name:      "multiple ranges",
+			spec:      "1-2, 4-5",
+			pageCount: 5,
+			expected:  []PageRange{{From: 1, Thru: 2}, {From: 4, Thru: 5}},
+		},
+		{
+			name:      "open ended range",
+			spec:      "3-",
+			pageCount: 10,
+			expected:  []PageRange{{From: 3, Thru: 10}},
+		},
+		{
+			name:      "range from the first page",
+			spec:      "-2",
+			pageCount: 10,
+			expected:  []PageRange{{From: 1, Thru: 2}},
+		},
+		{
+			name:        "out of bounds",
+			spec:        "1-6",
+			pageCount:   5,
+			expectedErr: true,
+		},
+		{
+			name:        "reversed range",
+			spec:        "4-2",
+			pageCount:   5,
+			expectedErr: true,
+		},
+		{
+			name:        "not a number",
+			spec:        "a-b",
+			pageCount:   5,
+			expectedErr: true,
+		},
+		{
+			name:        "empty spec",
+			spec:        "",
+			pageCount:   5,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ranges, err := ParsePageRanges(tt.spec, tt.pageCount)
+			if tt.expectedErr {
+				assert.Error(t, err, "Expected an error but parsing succeeded")
+			} else {
+				assert.NoError(t, err, "Expected parsing to succeed but it failed")
+			}
+
+			assert.Equal(t, tt.expected, ranges)
+		})
+	}
+}
+
+func TestPageRangeString(t *testing.T) {
+	assert.Equal(t, "4", PageRange{From: 4, Thru: 4}.String())
+	assert.Equal(t, "1-3", PageRange{From: 1, Thru: 3}.String())
+}
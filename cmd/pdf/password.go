@@ -0,0 +1,74 @@
+package pdf
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+// ChangePassword only sets a new owner password on a PDF that opens without a password.
+func (p *PDFProcessor) ChangePassword(pdf, dir, oldPassword, newPassword, prefix string) (string, error) {
+	input := filepath.Join(dir, pdf)
+	content, err := os.ReadFile(filepath.Clean(input))
+	if err != nil {
+		return "", err
+	}
+
+	// check the old password first so a wrong password gets the usual error
+	ctx, err := api.ReadContext(bytes.NewReader(content), decryptionConfig(oldPassword))
+	if err != nil {
+		return "", invalidPDF(err)
+	}
+
+	var decrypted bytes.Buffer
+	if err := api.Decrypt(bytes.NewReader(content), &decrypted, decryptionConfig(oldPassword)); err != nil {
+		return "", invalidPDF(err)
+	}
+
+	rekeyed := &PDFProcessor{ownerPassword: newPassword, permissions: model.PermissionFlags(ctx.E.P)}
+	if err := rekeyed.keepAlgorithm(ctx); err != nil {
+		return "", invalidPDF(err)
+	}
+
+	userPassword := newPassword
+	if _, err := api.ReadContext(bytes.NewReader(content), decryptionConfig("")); err == nil {
+		userPassword = ""
+	}
+	conf := rekeyed.encryptionConfig(userPassword)
+
+	output, err := p.outputPath(input, prefix, "", allPages(func() (int, error) {
+		return api.PageCount(bytes.NewReader(decrypted.Bytes()), nil)
+	}))
+	if err != nil {
+		return "", invalidPDF(err)
+	}
+
+	err = p.writeFile(input, output, decryptionConfig(newPassword), func(w io.Writer) error {
+		return invalidPDF(api.Encrypt(bytes.NewReader(decrypted.Bytes()), w, conf))
+	})
+	if err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+func (p *PDFProcessor) keepAlgorithm(ctx *model.Context) error {
+	switch {
+	case ctx.E.V == 5:
+		return p.SetAlgorithm(AlgorithmAES, 256)
+	case ctx.E.V == 4 && ctx.AES4Streams:
+		return p.SetAlgorithm(AlgorithmAES, 128)
+	case ctx.E.V == 4:
+		return p.SetAlgorithm(AlgorithmRC4, 128)
+	case ctx.E.V == 1 || ctx.E.V == 2:
+		// the key length is optional and 40 bits when it's missing
+		return p.SetAlgorithm(AlgorithmRC4, cmp.Or(ctx.E.L, 40))
+	}
+	return errors.New("unsupported encryption of the PDF")
+}
@@ -0,0 +1,188 @@
+package pdf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangePassword(t *testing.T) {
+	tests := []struct {
+		name         string
+		pdf          string
+		pdfPrefix    string
+		oldPassword  string
+		newPassword  string
+		expectedFile string
+		expectedErr  bool
+		setupFile    []string
+	}{
+		{
+			name:         "successful password change",
+			pdf:          "test.pdf",
+			pdfPrefix:    "",
+			oldPassword:  "test",
+			newPassword:  "new",
+			expectedFile: "test.pdf",
+			expectedErr:  false,
+			setupFile:    []string{"test.pdf"},
+		},
+		{
+			name:         "successful password change with custom prefix",
+			pdf:          "test.pdf",
+			pdfPrefix:    "rekeyed-",
+			oldPassword:  "test",
+			newPassword:  "new",
+			expectedFile: "rekeyed-test.pdf",
+			expectedErr:  false,
+			setupFile:    []string{"test.pdf"},
+		},
+		{
+			name:         "wrong old password",
+			pdf:          "test.pdf",
+			pdfPrefix:    "",
+			oldPassword:  "wrong",
+			newPassword:  "new",
+			expectedFile: "",
+			expectedErr:  true,
+			setupFile:    []string{"test.pdf"},
+		},
+		{
+			name:         "No file provided",
+			pdf:          "",
+			pdfPrefix:    "",
+			oldPassword:  "test",
+			newPassword:  "new",
+			expectedFile: "",
+			expectedErr:  true,
+			setupFile:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: %s", tempDir)
+			createTestFiles(t, tempDir, tt.setupFile)
+			if tt.setupFile != nil {
+				encryptTestFiles(t, tempDir, tt.pdf, "test", "")
+			}
+
+			processor := NewPDFProcessor(changePassword).InPlace()
+			rekeyedPdf, err := processor.ChangePassword(tt.pdf, tempDir, tt.oldPassword, tt.newPassword, tt.pdfPrefix)
+			if tt.expectedErr {
+				assert.Error(t, err, "Expected an error but command ran successfully")
+			} else {
+				assert.NoError(t, err, "Expected to run successfully but it failed")
+
+				// the new password opens the PDF and the old one doesn't
+				_, err = NewPDFProcessor(decrypt).InPlace().DecryptPdf(rekeyedPdf, "", tt.oldPassword, "old-")
+				assert.Error(t, err, "Expected the old password to be rejected")
+				_, err = NewPDFProcessor(decrypt).InPlace().DecryptPdf(rekeyedPdf, "", tt.newPassword, "")
+				assert.NoError(t, err, "Expected the new password to decrypt the PDF")
+			}
+
+			expectedFile := tt.expectedFile
+			if expectedFile != "" && tt.pdfPrefix == "" {
+				// without a prefix the PDF is overwritten in its own directory
+				expectedFile = filepath.Join(tempDir, expectedFile)
+			}
+			assert.Equal(t, expectedFile, rekeyedPdf, "Expected PDF file: %s to be Equal to: %s", rekeyedPdf, expectedFile)
+		})
+	}
+}
+
+func TestChangePasswordKeepsEncryption(t *testing.T) {
+	tests := []struct {
+		name              string
+		userPassword      string
+		ownerPassword     string
+		algorithm         string
+		keyLength         int
+		oldPassword       string
+		expectedOpensFree bool
+	}{
+		{
+			name:              "owner password only",
+			ownerPassword:     "owner",
+			algorithm:         AlgorithmAES,
+			keyLength:         256,
+			oldPassword:       "owner",
+			expectedOpensFree: true,
+		},
+		{
+			name:          "different user and owner passwords with the owner password",
+			userPassword:  "user",
+			ownerPassword: "owner",
+			algorithm:     AlgorithmAES,
+			keyLength:     128,
+			oldPassword:   "owner",
+		},
+		{
+			name:          "different user and owner passwords with the user password",
+			userPassword:  "user",
+			ownerPassword: "owner",
+			algorithm:     AlgorithmRC4,
+			keyLength:     40,
+			oldPassword:   "user",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: %s", tempDir)
+			createTestFiles(t, tempDir, []string{"test.pdf"})
+
+			encryptor := NewPDFProcessor(encrypt).InPlace()
+			encryptor.SetOwnerPassword(tt.ownerPassword)
+			encryptor.SetPermissions(model.PermissionPrintRev2 | model.PermissionExtract)
+			assert.NoError(t, encryptor.SetAlgorithm(tt.algorithm, tt.keyLength))
+			_, err = encryptor.EncryptPdf("test.pdf", tempDir, tt.userPassword, "")
+			assert.NoError(t, err, "failed to encrypt test file")
+			original := readEncryption(t, "test.pdf", tt.oldPassword)
+
+			rekeyedPdf, err := NewPDFProcessor(changePassword).InPlace().ChangePassword("test.pdf", tempDir, tt.oldPassword, "new", "")
+			if !assert.NoError(t, err, "Expected to run successfully but it failed") {
+				return
+			}
+
+			rekeyed := readEncryption(t, rekeyedPdf, "new")
+			assert.Equal(t, original.V, rekeyed.V, "Expected the algorithm to be kept")
+			assert.Equal(t, original.L, rekeyed.L, "Expected the key length to be kept")
+			assert.Equal(t, original.P, rekeyed.P, "Expected the permissions to be kept")
+
+			_, err = api.ReadContextFile(rekeyedPdf)
+			if tt.expectedOpensFree {
+				assert.NoError(t, err, "Expected the PDF to open without a password")
+			} else {
+				assert.Error(t, err, "Expected the PDF to need a password")
+			}
+			_, err = NewPDFProcessor(decrypt).InPlace().DecryptPdf(rekeyedPdf, "", tt.oldPassword, "old-")
+			assert.Error(t, err, "Expected the old password to be rejected")
+		})
+	}
+}
+
+// readEncryption returns the encryption dictionary of an encrypted PDF.
+func readEncryption(t *testing.T, file, password string) *model.Enc {
+	ctx, err := api.ReadContextFile(file)
+	if err == nil {
+		// PDFs without a user password open without one
+		assert.NotNil(t, ctx.E, "Expected %s to be encrypted", file)
+		return ctx.E
+	}
+
+	f, err := os.Open(file)
+	assert.NoError(t, err)
+	defer f.Close()
+	ctx, err = api.ReadContext(f, decryptionConfig(password))
+	assert.NoError(t, err, "failed to read %s", file)
+	return ctx.E
+}
@@ -1,15 +1,34 @@
 package pdf
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"io"
+	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/gmskazi/pdfmc/cmd/utils"
 	"github.com/pdfcpu/pdfcpu/pkg/api"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 )
 
 type PDFProcessor struct {
-	logo string
+	logo          string
+	ownerPassword string
+	permissions   model.PermissionFlags
+	algorithm     string
+	keyLength     int
+	outputDir     string
+	subdir        string
+	nameTemplate  string
+	index         int
+	force         bool
+	inPlace       bool
+	backup        func(path string) error
+	created       func(path string) error
+	watermark     *Watermark
 }
 
 func NewPDFProcessor(logo string) *PDFProcessor {
@@ -25,76 +44,192 @@ func (p *PDFProcessor) pdfExtension(file string) string {
 	return file
 }
 
+func (p *PDFProcessor) PageCount(pdf, dir string) (int, error) {
+	pageCount, err := api.PageCountFile(filepath.Join(dir, pdf))
+	return pageCount, invalidPDF(err)
+}
+
 func (p *PDFProcessor) MergePdfs(pdfs []string, outputPdf string) (string, error) {
 	if len(pdfs) < 2 {
 		return "", errors.New("at least two PDF files are required to merge")
 	}
-	output := p.pdfExtension(outputPdf)
+	files, selections, err := p.validatePageSelections(pdfs)
+	if err != nil {
+		return "", err
+	}
 
-	if err := api.MergeCreateFile(pdfs, output, false, nil); err != nil {
+	output, err := p.outputPath(outputPdf, "", p.pdfExtension(outputPdf), allPages(func() (int, error) {
+		return mergedPageCount(files, selections)
+	}))
+	if err != nil {
 		return "", err
 	}
 
-	if err := api.ValidateFile(output, nil); err != nil {
+	err = p.writeFile("", output, nil, func(w io.Writer) error {
+		if hasPageSelections(selections) {
+			return p.mergePageSelections(files, selections, w)
+		}
+		return invalidPDF(api.Merge("", files, w, nil, false))
+	})
+	if err != nil {
 		return "", err
 	}
 	return output, nil
 }
 
-func (p *PDFProcessor) EncryptPdf(pdf, dir, password, prefix string) (string, error) {
-	var (
-		encryptedPdfName string
-		err              error
-	)
+// validatePageSelections checks every selection up front, so nothing is
+// written when one is invalid.
+func (p *PDFProcessor) validatePageSelections(pdfs []string) ([]string, [][]string, error) {
+	files := make([]string, len(pdfs))
+	selections := make([][]string, len(pdfs))
+
+	for i, pdf := range pdfs {
+		file, selection := utils.SplitPageSelection(pdf)
+		files[i] = file
+		if selection == "" {
+			continue
+		}
 
-	conf := model.NewAESConfiguration(password, password, 256)
+		pageCount, err := api.PageCountFile(file)
+		if err != nil {
+			return nil, nil, invalidPDF(err)
+		}
 
-	if prefix != "" {
-		encryptedPdfName = prefix + pdf
-		err = api.EncryptFile(filepath.Join(dir, pdf), encryptedPdfName, conf)
+		ranges, err := ParsePageRanges(selection, pageCount)
 		if err != nil {
-			return "", err
+			return nil, nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+		}
+
+		for _, r := range ranges {
+			selections[i] = append(selections[i], r.String())
 		}
-	} else {
-		encryptedPdfName = pdf
-		err = api.EncryptFile(filepath.Join(dir, pdf), "", conf)
+	}
+	return files, selections, nil
+}
+
+func mergedPageCount(files []string, selections [][]string) (int, error) {
+	total := 0
+	for i, file := range files {
+		pageCount, err := api.PageCountFile(file)
 		if err != nil {
-			return "", err
+			return 0, invalidPDF(err)
+		}
+		if len(selections[i]) == 0 {
+			total += pageCount
+			continue
+		}
+
+		ranges, err := ParsePageRanges(strings.Join(selections[i], ","), pageCount)
+		if err != nil {
+			return 0, err
+		}
+		for _, r := range ranges {
+			total += r.Thru - r.From + 1
 		}
 	}
+	return total, nil
+}
 
-	err = api.ValidateFile(pdf, conf)
-	if err != nil {
-		return "", err
+func hasPageSelections(selections [][]string) bool {
+	for _, selection := range selections {
+		if len(selection) > 0 {
+			return true
+		}
 	}
-	return encryptedPdfName, nil
+	return false
 }
 
-func (p *PDFProcessor) DecryptPdf(pdf, dir, password, prefix string) (string, error) {
-	var (
-		decryptedPdfName string
-		err              error
-	)
-	conf := model.NewAESConfiguration(password, password, 256)
-
-	if prefix != "" {
-		decryptedPdfName = prefix + pdf
-		err = api.DecryptFile(filepath.Join(dir, pdf), decryptedPdfName, conf)
+func (p *PDFProcessor) mergePageSelections(files []string, selections [][]string, w io.Writer) error {
+	var readers []io.ReadSeeker
+
+	for i, file := range files {
+		content, err := os.ReadFile(filepath.Clean(file))
 		if err != nil {
-			return "", err
+			return err
 		}
 
-	} else {
-		decryptedPdfName = pdf
-		err = api.DecryptFile(filepath.Join(dir, pdf), "", conf)
-		if err != nil {
-			return "", err
+		if len(selections[i]) == 0 {
+			readers = append(readers, bytes.NewReader(content))
+			continue
+		}
+
+		var collected bytes.Buffer
+		if err := api.Collect(bytes.NewReader(content), &collected, selections[i], nil); err != nil {
+			return invalidPDF(err)
+		}
+		readers = append(readers, bytes.NewReader(collected.Bytes()))
+	}
+
+	return invalidPDF(api.MergeRaw(readers, w, false, nil))
+}
+
+func (p *PDFProcessor) EncryptPdf(pdf, dir, password, prefix string) (string, error) {
+	p = p.inSubdir(dir, pdf)
+	conf := p.encryptionConfig(password)
+	input := filepath.Join(dir, pdf)
+
+	output, err := p.outputPath(input, prefix, "", allPages(func() (int, error) {
+		return api.PageCountFile(input)
+	}))
+	if err != nil {
+		return "", invalidPDF(err)
+	}
+
+	content, err := os.ReadFile(filepath.Clean(input))
+	if err != nil {
+		return "", err
+	}
+
+	// the watermark can't be added once the PDF is encrypted
+	if p.watermark != nil {
+		var watermarked bytes.Buffer
+		if err := p.watermark.stamp(content, &watermarked); err != nil {
+			return "", fmt.Errorf("%s: %w", filepath.Base(input), err)
 		}
+		content = watermarked.Bytes()
 	}
 
-	err = api.ValidateFile(decryptedPdfName, conf)
+	err = p.writeFile(input, output, conf, func(w io.Writer) error {
+		return invalidPDF(api.Encrypt(bytes.NewReader(content), w, conf))
+	})
 	if err != nil {
 		return "", err
 	}
-	return decryptedPdfName, nil
+	return output, nil
+}
+
+func (p *PDFProcessor) DecryptPdf(pdf, dir, password, prefix string) (string, error) {
+	p = p.inSubdir(dir, pdf)
+	conf := decryptionConfig(password)
+	input := filepath.Join(dir, pdf)
+
+	output, err := p.outputPath(input, prefix, "", allPages(func() (int, error) {
+		return pageCount(input, decryptionConfig(password))
+	}))
+	if err != nil {
+		return "", invalidPDF(err)
+	}
+
+	content, err := os.ReadFile(filepath.Clean(input))
+	if err != nil {
+		return "", err
+	}
+
+	err = p.writeFile(input, output, conf, func(w io.Writer) error {
+		return invalidPDF(api.Decrypt(bytes.NewReader(content), w, conf))
+	})
+	if err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+func pageCount(input string, conf *model.Configuration) (int, error) {
+	f, err := os.Open(filepath.Clean(input))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return api.PageCount(f, conf)
 }
@@ -6,13 +6,20 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/pdfcpu/pdfcpu/pkg/api"
 	"github.com/stretchr/testify/assert"
 )
 
 const (
-	merge   = "merge"
-	encrypt = "encrypt"
-	decrypt = "decrypt"
+	merge     = "merge"
+	encrypt   = "encrypt"
+	decrypt   = "decrypt"
+	split     = "split"
+	rotate    = "rotate"
+	optimize  = "optimize"
+	watermark = "watermark"
+
+	changePassword = "change-password"
 )
 
 func createValidPDF(filepath string) error {
@@ -55,7 +62,7 @@ func createTestFiles(t *testing.T, tempDir string, pdfs []string) {
 }
 
 func encryptTestFiles(t *testing.T, tempdir, pdf, password, pdfPrefix string) {
-	p := NewPDFProcessor(encrypt)
+	p := NewPDFProcessor(encrypt).InPlace()
 	// encrypt test files
 	encryptedPdf, err := p.EncryptPdf(pdf, tempdir, password, pdfPrefix)
 	assert.NoError(t, err, "failed to encrypt pdf")
@@ -99,6 +106,22 @@ func TestMergePdfs(t *testing.T) {
 			expectedErr: true,
 			setupFile:   nil,
 		},
+		{
+			name:        "Merge 2 files with page selections",
+			pdfs:        []string{file1 + "[1]", file2 + "[1-]"},
+			customName:  "test",
+			fileOutput:  "test.pdf",
+			expectedErr: false,
+			setupFile:   []string{file1, file2},
+		},
+		{
+			name:        "Page selection out of bounds",
+			pdfs:        []string{file1 + "[1-2]", file2},
+			customName:  "test",
+			fileOutput:  "",
+			expectedErr: true,
+			setupFile:   []string{file1, file2},
+		},
 	}
 
 	for _, tt := range tests {
@@ -205,7 +228,7 @@ func TestEncryptPdf(t *testing.T) {
 			assert.NoError(t, err, "failed to change directory: %s", tempDir)
 			createTestFiles(t, tempDir, tt.setupFile)
 
-			processor := NewPDFProcessor(encrypt)
+			processor := NewPDFProcessor(encrypt).InPlace()
 			encryptedPdf, err := processor.EncryptPdf(tt.pdf, tempDir, tt.password, tt.pdfPrefix)
 			fmt.Println(err)
 			if tt.expectedErr {
@@ -214,7 +237,12 @@ func TestEncryptPdf(t *testing.T) {
 				assert.NoError(t, err, "Expected to run successfully but it failed")
 			}
 
-			assert.Equal(t, tt.expectedFile, encryptedPdf, "Expected PDF file: %s to be Equal to: %s", encryptedPdf, tt.expectedFile)
+			expectedFile := tt.expectedFile
+			if expectedFile != "" && tt.pdfPrefix == "" {
+				// without a prefix the PDF is overwritten in its own directory
+				expectedFile = filepath.Join(tempDir, expectedFile)
+			}
+			assert.Equal(t, expectedFile, encryptedPdf, "Expected PDF file: %s to be Equal to: %s", encryptedPdf, expectedFile)
 		})
 	}
 }
@@ -277,7 +305,7 @@ func TestDecryptPdf(t *testing.T) {
 				encryptTestFiles(t, tempDir, tt.pdf, tt.password, "")
 			}
 
-			processor := NewPDFProcessor(decrypt)
+			processor := NewPDFProcessor(decrypt).InPlace()
 			decryptedPdf, err := processor.DecryptPdf(tt.pdf, tempDir, tt.password, tt.pdfPrefix)
 			if tt.expectedErr {
 				assert.Error(t, err, "Expected an error but command ran successfully")
@@ -285,7 +313,41 @@ func TestDecryptPdf(t *testing.T) {
 				assert.NoError(t, err, "Expected to run successfully but it failed")
 			}
 
-			assert.Equal(t, tt.expectedFile, decryptedPdf, "Expected PDF file: %s to be Equal to: %s", decryptedPdf, tt.expectedFile)
+			expectedFile := tt.expectedFile
+			if expectedFile != "" && tt.pdfPrefix == "" {
+				// without a prefix the PDF is overwritten in its own directory
+				expectedFile = filepath.Join(tempDir, expectedFile)
+			}
+			assert.Equal(t, expectedFile, decryptedPdf, "Expected PDF file: %s to be Equal to: %s", decryptedPdf, expectedFile)
 		})
 	}
 }
+
+func TestEncryptPdfOwnerPassword(t *testing.T) {
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: %s", tempDir)
+	createTestFiles(t, tempDir, []string{"test.pdf"})
+
+	permissions, err := ParsePermissions("print")
+	assert.NoError(t, err)
+
+	processor := NewPDFProcessor(encrypt).InPlace()
+	processor.SetOwnerPassword("owner")
+	processor.SetPermissions(permissions)
+
+	encryptedPdf, err := processor.EncryptPdf("test.pdf", tempDir, "", "")
+	assert.NoError(t, err, "Expected to run successfully but it failed")
+	assert.Equal(t, filepath.Join(tempDir, "test.pdf"), encryptedPdf)
+
+	// the PDF opens without a password but is still encrypted
+	_, err = api.PageCountFile(encryptedPdf)
+	assert.NoError(t, err, "Expected the PDF to open without a password")
+
+	_, err = processor.EncryptPdf("test.pdf", tempDir, "", "")
+	assert.ErrorContains(t, err, "already encrypted")
+
+	decryptedPdf, err := NewPDFProcessor(decrypt).InPlace().DecryptPdf("test.pdf", tempDir, "owner", "")
+	assert.NoError(t, err, "Expected the owner password to decrypt the PDF")
+	assert.Equal(t, filepath.Join(tempDir, "test.pdf"), decryptedPdf)
+}
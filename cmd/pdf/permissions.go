@@ -0,0 +1,51 @@
+package pdf
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+var permissionFlags = map[string]model.PermissionFlags{
+	"print":              model.PermissionPrintRev2,
+	"print-high-quality": model.PermissionPrintRev2 | model.PermissionPrintRev3,
+	"modify":             model.PermissionModify,
+	"copy":               model.PermissionExtract | model.PermissionExtractRev3,
+	"extract":            model.PermissionExtract | model.PermissionExtractRev3,
+	"annotate":           model.PermissionModAnnFillForm,
+	"fill-forms":         model.PermissionFillRev3,
+	"assemble":           model.PermissionAssembleRev3,
+}
+
+func PermissionNames() []string {
+	names := []string{"all", "none"}
+	for name := range permissionFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names[2:])
+	return names
+}
+
+func ParsePermissions(spec string) (model.PermissionFlags, error) {
+	permissions := model.PermissionsNone
+
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "", "none":
+			continue
+		case "all":
+			permissions = model.PermissionsAll
+			continue
+		}
+
+		flag, ok := permissionFlags[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown permission %q, valid permissions are: %s", name, strings.Join(PermissionNames(), ", "))
+		}
+		permissions |= flag
+	}
+	return permissions, nil
+}
@@ -0,0 +1,56 @@
+package pdf
+
+import (
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		expected    model.PermissionFlags
+		expectedErr bool
+	}{
+		{
+			name:     "no permissions",
+			spec:     "none",
+			expected: model.PermissionsNone,
+		},
+		{
+			name:     "all permissions",
+			spec:     "all",
+			expected: model.PermissionsAll,
+		},
+		{
+			name:     "print only",
+			spec:     "print",
+			expected: model.PermissionsNone | model.PermissionPrintRev2,
+		},
+		{
+			name:     "print high quality and copy",
+			spec:     "print-high-quality, Copy",
+			expected: model.PermissionsPrint | model.PermissionExtract | model.PermissionExtractRev3,
+		},
+		{
+			name:        "unknown permission",
+			spec:        "print,delete",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			permissions, err := ParsePermissions(tt.spec)
+			if tt.expectedErr {
+				assert.Error(t, err, "Expected an error but parsing succeeded")
+			} else {
+				assert.NoError(t, err, "Expected parsing to succeed but it failed")
+			}
+
+			assert.Equal(t, tt.expected, permissions)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gmskazi/pdfmc/cmd/utils"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+type PlannedPage struct {
+	File     string
+	Page     int
+	Rotation int
+}
+
+func ExpandPages(pdfs []string) ([]PlannedPage, error) {
+	var pages []PlannedPage
+
+	for _, pdf := range pdfs {
+		file, selection := utils.SplitPageSelection(pdf)
+		pageCount, err := api.PageCountFile(file)
+		if err != nil {
+			return nil, invalidPDF(err)
+		}
+
+		ranges := []PageRange{{From: 1, Thru: pageCount}}
+		if selection != "" {
+			ranges, err = ParsePageRanges(selection, pageCount)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", filepath.Base(file), err)
+			}
+		}
+
+		for _, r := range ranges {
+			for page := r.From; page <= r.Thru; page++ {
+				pages = append(pages, PlannedPage{File: file, Page: page})
+			}
+		}
+	}
+	return pages, nil
+}
+
+func (p *PDFProcessor) MergePages(pages []PlannedPage, outputPdf string) (string, error) {
+	if len(pages) == 0 {
+		return "", errors.New("no pages left to merge")
+	}
+
+	output, err := p.outputPath(outputPdf, "", p.pdfExtension(outputPdf), allPages(func() (int, error) {
+		return len(pages), nil
+	}))
+	if err != nil {
+		return "", err
+	}
+
+	err = p.writeFile("", output, nil, func(w io.Writer) error {
+		return p.mergePlan(pages, w)
+	})
+	if err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+// mergePlan collects every run of pages from the same file in a single pass.
+func (p *PDFProcessor) mergePlan(pages []PlannedPage, w io.Writer) error {
+	var readers []io.ReadSeeker
+	contents := make(map[string][]byte)
+
+	for start := 0; start < len(pages); {
+		end := start + 1
+		for end < len(pages) && pages[end].File == pages[start].File {
+			end++
+		}
+		run := pages[start:end]
+		start = end
+
+		content, ok := contents[run[0].File]
+		if !ok {
+			var err error
+			content, err = os.ReadFile(filepath.Clean(run[0].File))
+			if err != nil {
+				return err
+			}
+			contents[run[0].File] = content
+		}
+
+		selection := make([]string, len(run))
+		for i, page := range run {
+			selection[i] = strconv.Itoa(page.Page)
+		}
+
+		var collected bytes.Buffer
+		if err := api.Collect(bytes.NewReader(content), &collected, selection, nil); err != nil {
+			return invalidPDF(err)
+		}
+
+		rotated, err := rotateRun(collected.Bytes(), run)
+		if err != nil {
+			return err
+		}
+		readers = append(readers, bytes.NewReader(rotated))
+	}
+
+	if len(readers) == 1 {
+		_, err := io.Copy(w, readers[0])
+		return err
+	}
+	return invalidPDF(api.MergeRaw(readers, w, false, nil))
+}
+
+func rotateRun(content []byte, run []PlannedPage) ([]byte, error) {
+	byRotation := make(map[int][]string)
+	for i, page := range run {
+		if rotation := normalizeRotation(page.Rotation); rotation != 0 {
+			byRotation[rotation] = append(byRotation[rotation], strconv.Itoa(i+1))
+		}
+	}
+
+	for _, rotation := range []int{90, 180, 270} {
+		if len(byRotation[rotation]) == 0 {
+			continue
+		}
+
+		var rotated bytes.Buffer
+		if err := api.Rotate(bytes.NewReader(content), &rotated, rotation, byRotation[rotation], nil); err != nil {
+			return nil, invalidPDF(err)
+		}
+		content = rotated.Bytes()
+	}
+	return content, nil
+}
+
+func normalizeRotation(rotation int) int {
+	return ((rotation % 360) + 360) % 360
+}
@@ -0,0 +1,68 @@
+package pdf
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPages(t *testing.T) {
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+	createMultiPagePDF(t, tempDir, "three.pdf", 3)
+	err = createValidPDF(filepath.Join(tempDir, "single.pdf"))
+	assert.NoError(t, err)
+
+	pages, err := ExpandPages([]string{"three.pdf[3,1]", "single.pdf"})
+	assert.NoError(t, err)
+	assert.Equal(t, []PlannedPage{
+		{File: "three.pdf", Page: 3},
+		{File: "three.pdf", Page: 1},
+		{File: "single.pdf", Page: 1},
+	}, pages)
+
+	_, err = ExpandPages([]string{"three.pdf[4]"})
+	assert.Error(t, err, "page selection out of bounds")
+
+	_, err = ExpandPages([]string{"missing.pdf"})
+	assert.Error(t, err, "missing file")
+}
+
+func TestMergePages(t *testing.T) {
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+	createMultiPagePDF(t, tempDir, "three.pdf", 3)
+	err = createValidPDF(filepath.Join(tempDir, "single.pdf"))
+	assert.NoError(t, err)
+
+	plan := []PlannedPage{
+		{File: "three.pdf", Page: 3},
+		{File: "three.pdf", Page: 1, Rotation: 90},
+		{File: "single.pdf", Page: 1, Rotation: -90},
+		{File: "three.pdf", Page: 1, Rotation: 180},
+	}
+
+	output, err := NewPDFProcessor(merge).MergePages(plan, "packet")
+	assert.NoError(t, err)
+	assert.Equal(t, "packet.pdf", output)
+
+	ctx, err := api.ReadContextFile(filepath.Join(tempDir, output))
+	assert.NoError(t, err)
+	assert.Equal(t, len(plan), ctx.PageCount)
+
+	for i, rotation := range []int{0, 90, 270, 180} {
+		_, _, attrs, err := ctx.PageDict(i+1, false)
+		assert.NoError(t, err)
+		assert.Equal(t, rotation, attrs.Rotate, "rotation of page %d", i+1)
+	}
+
+	_, err = NewPDFProcessor(merge).MergePages(nil, "empty")
+	assert.Error(t, err, "nothing to merge")
+}
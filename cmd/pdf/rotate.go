@@ -0,0 +1,111 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+type Rotation struct {
+	Degrees      int
+	Pages        string
+	AutoPortrait bool
+}
+
+func (p *PDFProcessor) RotatePdf(pdf, dir, prefix string, rotation Rotation) (string, error) {
+	p = p.inSubdir(dir, pdf)
+	input := filepath.Join(dir, pdf)
+
+	content, err := os.ReadFile(filepath.Clean(input))
+	if err != nil {
+		return "", err
+	}
+	if err := checkNoPassword(input, content); err != nil {
+		return "", err
+	}
+
+	pages, err := rotatedPages(content, rotation)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", filepath.Base(input), err)
+	}
+
+	output, err := p.outputPath(input, prefix, "", allPages(func() (int, error) {
+		return api.PageCount(bytes.NewReader(content), nil)
+	}))
+	if err != nil {
+		return "", invalidPDF(err)
+	}
+
+	err = p.writeFile(input, output, nil, func(w io.Writer) error {
+		// pdfcpu rotates every page for an empty selection
+		if len(pages) == 0 {
+			_, err := w.Write(content)
+			return err
+		}
+		return invalidPDF(api.Rotate(bytes.NewReader(content), w, rotation.Degrees, pages, nil))
+	})
+	if err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+func rotatedPages(content []byte, rotation Rotation) ([]string, error) {
+	ctx, err := api.ReadValidateAndOptimize(bytes.NewReader(content), model.NewDefaultConfiguration())
+	if err != nil {
+		return nil, invalidPDF(err)
+	}
+
+	ranges := []PageRange{{From: 1, Thru: ctx.PageCount}}
+	if rotation.Pages != "" {
+		ranges, err = ParsePageRanges(rotation.Pages, ctx.PageCount)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var pages []string
+	for _, r := range ranges {
+		for page := r.From; page <= r.Thru; page++ {
+			if rotation.AutoPortrait {
+				landscape, err := isLandscape(ctx, page)
+				if err != nil {
+					return nil, invalidPDF(err)
+				}
+				if !landscape {
+					continue
+				}
+			}
+			pages = append(pages, strconv.Itoa(page))
+		}
+	}
+	return pages, nil
+}
+
+// isLandscape takes the rotation the page already has into account.
+func isLandscape(ctx *model.Context, page int) (bool, error) {
+	_, _, attrs, err := ctx.PageDict(page, false)
+	if err != nil {
+		return false, err
+	}
+
+	box := attrs.CropBox
+	if box == nil {
+		box = attrs.MediaBox
+	}
+	if box == nil {
+		return false, nil
+	}
+
+	landscape := box.Width() > box.Height()
+	if normalizeRotation(attrs.Rotate)%180 == 90 {
+		landscape = !landscape
+	}
+	return landscape, nil
+}
@@ -0,0 +1,96 @@
+package pdf
+
+import (
+	"os"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/stretchr/testify/assert"
+)
+
+// pageRotations returns the rotation of every page of a PDF.
+func pageRotations(t *testing.T, file string) []int {
+	ctx, err := api.ReadContextFile(file)
+	assert.NoError(t, err)
+
+	var rotations []int
+	for page := 1; page <= ctx.PageCount; page++ {
+		_, _, attrs, err := ctx.PageDict(page, false)
+		assert.NoError(t, err)
+		rotations = append(rotations, attrs.Rotate)
+	}
+	return rotations
+}
+
+func TestRotatePdf(t *testing.T) {
+	tests := []struct {
+		name        string
+		rotation    Rotation
+		prefix      string
+		output      string
+		rotations   []int
+		expectedErr bool
+	}{
+		{
+			name:      "Rotate every page",
+			rotation:  Rotation{Degrees: 180},
+			prefix:    "rotated-",
+			output:    "rotated-three.pdf",
+			rotations: []int{180, 270, 180},
+		},
+		{
+			name:      "Rotate a page range",
+			rotation:  Rotation{Degrees: 90, Pages: "1,3"},
+			prefix:    "rotated-",
+			output:    "rotated-three.pdf",
+			rotations: []int{90, 90, 90},
+		},
+		{
+			name:      "Rotate landscape pages to portrait",
+			rotation:  Rotation{Degrees: 270, AutoPortrait: true},
+			prefix:    "rotated-",
+			output:    "rotated-three.pdf",
+			rotations: []int{0, 0, 0},
+		},
+		{
+			name:      "Nothing to rotate in the page range",
+			rotation:  Rotation{Degrees: 90, Pages: "3", AutoPortrait: true},
+			prefix:    "rotated-",
+			output:    "rotated-three.pdf",
+			rotations: []int{0, 90, 0},
+		},
+		{
+			name:        "Page range out of bounds",
+			rotation:    Rotation{Degrees: 90, Pages: "4"},
+			prefix:      "rotated-",
+			expectedErr: true,
+		},
+		{
+			name:        "Overwriting the original needs in place",
+			rotation:    Rotation{Degrees: 90},
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+			// the second page is shown in landscape
+			createMultiPagePDF(t, tempDir, "merged.pdf", 3)
+			err = api.RotateFile("merged.pdf", "three.pdf", 90, []string{"2"}, nil)
+			assert.NoError(t, err)
+
+			output, err := NewPDFProcessor(rotate).RotatePdf("three.pdf", tempDir, tt.prefix, tt.rotation)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.output, output)
+			assert.Equal(t, tt.rotations, pageRotations(t, output))
+		})
+	}
+}
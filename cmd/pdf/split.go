@@ -0,0 +1,138 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+)
+
+func (p *PDFProcessor) writePageRanges(input, prefix string, ranges []PageRange, names []string) ([]string, error) {
+	var outputs []string
+
+	content, err := os.ReadFile(filepath.Clean(input))
+	if err != nil {
+		return nil, err
+	}
+
+	// check every part up front so a PDF is never split halfway
+	for i, r := range ranges {
+		suffix := r.String()
+		if names != nil {
+			suffix = names[i]
+		}
+
+		output, err := p.outputPath(input, prefix, stem(input)+"_"+suffix+".pdf", func() (string, error) {
+			return r.String(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkOverwrite(input, output); err != nil {
+			return nil, err
+		}
+		outputs = append(outputs, output)
+	}
+
+	for i, r := range ranges {
+		err = p.writeFile(input, outputs[i], nil, func(w io.Writer) error {
+			return invalidPDF(api.Trim(bytes.NewReader(content), w, []string{r.String()}, nil))
+		})
+		if err != nil {
+			return outputs[:i], err
+		}
+	}
+	return outputs, nil
+}
+
+func (p *PDFProcessor) SplitPdfEvery(pdf, dir, prefix string, span int) ([]string, error) {
+	if span < 1 {
+		return nil, errors.New("the number of pages per file must be at least 1")
+	}
+
+	input := filepath.Join(dir, pdf)
+	pageCount, err := api.PageCountFile(input)
+	if err != nil {
+		return nil, invalidPDF(err)
+	}
+
+	var ranges []PageRange
+	for from := 1; from <= pageCount; from += span {
+		ranges = append(ranges, PageRange{From: from, Thru: min(from+span-1, pageCount)})
+	}
+
+	return p.writePageRanges(input, prefix, ranges, nil)
+}
+
+func (p *PDFProcessor) SplitPdfByRanges(pdf, dir, prefix, spec string) ([]string, error) {
+	input := filepath.Join(dir, pdf)
+	pageCount, err := api.PageCountFile(input)
+	if err != nil {
+		return nil, invalidPDF(err)
+	}
+
+	ranges, err := ParsePageRanges(spec, pageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.writePageRanges(input, prefix, ranges, nil)
+}
+
+func (p *PDFProcessor) SplitPdfByBookmarks(pdf, dir, prefix string) ([]string, error) {
+	input := filepath.Join(dir, pdf)
+
+	pageCount, err := api.PageCountFile(input)
+	if err != nil {
+		return nil, invalidPDF(err)
+	}
+
+	f, err := os.Open(filepath.Clean(input))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	bookmarks, err := api.Bookmarks(f, nil)
+	if err != nil {
+		return nil, invalidPDF(err)
+	}
+	if len(bookmarks) == 0 {
+		return nil, fmt.Errorf("%s has no bookmarks to split on", pdf)
+	}
+
+	var (
+		ranges []PageRange
+		names  []string
+	)
+	for i, bm := range bookmarks {
+		thru := bm.PageThru
+		if thru == 0 {
+			thru = pageCount
+		}
+		r := PageRange{From: bm.PageFrom, Thru: thru}
+		ranges = append(ranges, r)
+		names = append(names, fmt.Sprintf("%02d_%s", i+1, bookmarkFileName(bm.Title, r)))
+	}
+
+	return p.writePageRanges(input, prefix, ranges, names)
+}
+
+func bookmarkFileName(title string, r PageRange) string {
+	name := strings.Map(func(c rune) rune {
+		if unicode.IsSpace(c) || unicode.IsControl(c) || strings.ContainsRune(`/\:?*<>|"`, c) {
+			return '_'
+		}
+		return c
+	}, strings.TrimSpace(title))
+	if name == "" {
+		return r.String()
+	}
+	return name
+}
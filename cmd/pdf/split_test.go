@@ -0,0 +1,155 @@
+package pdf
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// createMultiPagePDF merges single page PDFs so the output has one page and
+// one top level bookmark per input file.
+func createMultiPagePDF(t *testing.T, tempDir, output string, pages int) {
+	var pdfs []string
+	for i := 0; i < pages; i++ {
+		pdfs = append(pdfs, "page"+string(rune('a'+i))+".pdf")
+	}
+	createTestFiles(t, tempDir, pdfs)
+
+	_, err := NewPDFProcessor(merge).MergePdfs(pdfs, output)
+	assert.NoError(t, err, "failed to create multi page pdf")
+}
+
+func TestSplitPdfEvery(t *testing.T) {
+	tests := []struct {
+		name          string
+		span          int
+		expectedFiles []string
+		expectedErr   bool
+	}{
+		{
+			name:          "split every page",
+			span:          1,
+			expectedFiles: []string{"doc_1.pdf", "doc_2.pdf", "doc_3.pdf"},
+		},
+		{
+			name:          "split every two pages",
+			span:          2,
+			expectedFiles: []string{"doc_1-2.pdf", "doc_3.pdf"},
+		},
+		{
+			name:        "invalid span",
+			span:        0,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: %s", tempDir)
+			createMultiPagePDF(t, tempDir, "doc", 3)
+
+			processor := NewPDFProcessor(split)
+			files, err := processor.SplitPdfEvery("doc.pdf", tempDir, "", tt.span)
+			if tt.expectedErr {
+				assert.Error(t, err, "Expected an error but command ran successfully")
+			} else {
+				assert.NoError(t, err, "Expected to run successfully but it failed")
+			}
+
+			assert.Equal(t, tt.expectedFiles, files)
+			for _, f := range files {
+				assert.FileExists(t, f)
+			}
+		})
+	}
+}
+
+func TestSplitPdfByRanges(t *testing.T) {
+	tests := []struct {
+		name          string
+		ranges        string
+		prefix        string
+		expectedFiles []string
+		expectedErr   bool
+	}{
+		{
+			name:          "split by ranges",
+			ranges:        "1,2-",
+			expectedFiles: []string{"doc_1.pdf", "doc_2-3.pdf"},
+		},
+		{
+			name:          "split by ranges with prefix",
+			ranges:        "1-2",
+			prefix:        "split-",
+			expectedFiles: []string{"split-doc_1-2.pdf"},
+		},
+		{
+			name:        "range out of bounds",
+			ranges:      "2-5",
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: %s", tempDir)
+			createMultiPagePDF(t, tempDir, "doc", 3)
+
+			processor := NewPDFProcessor(split)
+			files, err := processor.SplitPdfByRanges("doc.pdf", tempDir, tt.prefix, tt.ranges)
+			if tt.expectedErr {
+				assert.Error(t, err, "Expected an error but command ran successfully")
+			} else {
+				assert.NoError(t, err, "Expected to run successfully but it failed")
+			}
+
+			assert.Equal(t, tt.expectedFiles, files)
+			for _, f := range files {
+				assert.FileExists(t, f)
+			}
+		})
+	}
+}
+
+func TestSplitPdfByBookmarks(t *testing.T) {
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: %s", tempDir)
+	createMultiPagePDF(t, tempDir, "doc", 2)
+
+	processor := NewPDFProcessor(split)
+	files, err := processor.SplitPdfByBookmarks("doc.pdf", tempDir, "")
+	assert.NoError(t, err, "Expected to run successfully but it failed")
+	assert.Len(t, files, 2)
+	for _, f := range files {
+		assert.FileExists(t, f)
+	}
+
+	_, err = processor.SplitPdfByBookmarks("pagea.pdf", tempDir, "")
+	assert.Error(t, err, "Expected an error for a PDF without bookmarks")
+}
+
+func TestBookmarkFileName(t *testing.T) {
+	tests := []struct {
+		title    string
+		expected string
+	}{
+		{title: "Chapter 1", expected: "Chapter_1"},
+		{title: " Intro: a/b\\c ", expected: "Intro__a_b_c"},
+		{title: `What? *Really* <maybe> | "yes"`, expected: "What___Really___maybe_____yes_"},
+		{title: "tab\tnew\nline\x00", expected: "tab_new_line_"},
+		{title: "", expected: "3-5"},
+		{title: "  ", expected: "3-5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.expected, bookmarkFileName(tt.title, PageRange{From: 3, Thru: 5}))
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/color"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+var WatermarkPositions = []string{"tl", "tc", "tr", "l", "c", "r", "bl", "bc", "br"}
+
+type Watermark struct {
+	// Text is stamped when it's set, otherwise the Image file is
+	Text    string
+	Image   string
+	Font    string
+	Size    int
+	Color   string
+	Opacity float64
+	// Rotation is counterclockwise, in degrees
+	Rotation float64
+	Position string
+	// Scale is the width of an image relative to the page
+	Scale    float64
+	Underlay bool
+	Pages    string
+}
+
+func DefaultWatermark() Watermark {
+	return Watermark{
+		Font:     "Helvetica",
+		Size:     48,
+		Color:    "#808080",
+		Opacity:  0.5,
+		Rotation: 45,
+		Position: "c",
+		Scale:    0.5,
+	}
+}
+
+func (wm Watermark) Check() error {
+	if wm.Text != "" {
+		if !font.SupportedFont(wm.Font) {
+			return fmt.Errorf("unknown font %s, use one of: %s", wm.Font, strings.Join(font.CoreFontNames(), ", "))
+		}
+		if wm.Size <= 0 {
+			return errors.New("the font size must be more than 0")
+		}
+		if _, err := color.ParseColor(wm.Color); err != nil {
+			return fmt.Errorf("invalid color %s, use a name like red or a code like #ff0000", wm.Color)
+		}
+	}
+	if wm.Opacity < 0 || wm.Opacity > 1 {
+		return errors.New("the opacity must be between 0 and 1")
+	}
+	if wm.Rotation < -180 || wm.Rotation > 180 {
+		return errors.New("the rotation must be between -180 and 180 degrees")
+	}
+	if _, err := types.ParsePositionAnchor(wm.Position); err != nil || wm.Position == "f" || wm.Position == "full" {
+		return fmt.Errorf("unknown position %s, use one of: %s", wm.Position, strings.Join(WatermarkPositions, ", "))
+	}
+	if wm.Scale <= 0 || wm.Scale > 1 {
+		return errors.New("the scale must be more than 0 and at most 1")
+	}
+	if wm.Pages != "" {
+		if _, err := ParsePageRanges(wm.Pages, math.MaxInt32); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *PDFProcessor) SetWatermark(wm Watermark) {
+	p.watermark = &wm
+}
+
+func (p *PDFProcessor) WatermarkPdf(pdf, dir, prefix string, wm Watermark) (string, error) {
+	p = p.inSubdir(dir, pdf)
+	input := filepath.Join(dir, pdf)
+
+	content, err := os.ReadFile(filepath.Clean(input))
+	if err != nil {
+		return "", err
+	}
+	if err := checkNoPassword(input, content); err != nil {
+		return "", err
+	}
+
+	output, err := p.outputPath(input, prefix, "", allPages(func() (int, error) {
+		return api.PageCount(bytes.NewReader(content), nil)
+	}))
+	if err != nil {
+		return "", invalidPDF(err)
+	}
+
+	err = p.writeFile(input, output, nil, func(w io.Writer) error {
+		if err := wm.stamp(content, w); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(input), err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+func (wm Watermark) stamp(content []byte, w io.Writer) error {
+	pages, err := wm.selectedPages(content)
+	if err != nil {
+		return err
+	}
+
+	watermark, err := wm.model()
+	if err != nil {
+		return err
+	}
+	return invalidPDF(api.AddWatermarks(bytes.NewReader(content), w, pages, watermark, nil))
+}
+
+// selectedPages returns nil to select every page.
+func (wm Watermark) selectedPages(content []byte) ([]string, error) {
+	if wm.Pages == "" {
+		return nil, nil
+	}
+
+	count, err := api.PageCount(bytes.NewReader(content), nil)
+	if err != nil {
+		return nil, invalidPDF(err)
+	}
+	ranges, err := ParsePageRanges(wm.Pages, count)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		pages = append(pages, fmt.Sprintf("%d-%d", r.From, r.Thru))
+	}
+	return pages, nil
+}
+
+func (wm Watermark) model() (*model.Watermark, error) {
+	desc := fmt.Sprintf("opacity:%g, rotation:%g, position:%s", wm.Opacity, wm.Rotation, wm.Position)
+	onTop := !wm.Underlay
+
+	if wm.Text == "" {
+		img, err := os.ReadFile(filepath.Clean(wm.Image))
+		if err != nil {
+			return nil, err
+		}
+		desc += fmt.Sprintf(", scalefactor:%g rel", wm.Scale)
+		return api.ImageWatermarkForReader(bytes.NewReader(img), desc, onTop, false, types.POINTS)
+	}
+
+	// the text is as large as the font size instead of a part of the page
+	desc += fmt.Sprintf(", fontname:%s, points:%d, fillcolor:%s, scalefactor:1 abs", wm.Font, wm.Size, wm.Color)
+	return api.TextWatermark(wm.Text, desc, onTop, false, types.POINTS)
+}
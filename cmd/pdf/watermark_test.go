@@ -0,0 +1,186 @@
+package pdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// watermarkedPages reports for every page of a PDF whether it has a
+// watermark, pdfcpu marks the watermarks as artifacts in the content.
+func watermarkedPages(t *testing.T, file string, conf *model.Configuration) []bool {
+	f, err := os.Open(file)
+	assert.NoError(t, err)
+	defer f.Close()
+
+	if conf == nil {
+		conf = model.NewDefaultConfiguration()
+	}
+	ctx, err := api.ReadValidateAndOptimize(f, conf)
+	assert.NoError(t, err)
+
+	var watermarked []bool
+	for page := 1; page <= ctx.PageCount; page++ {
+		r, err := pdfcpu.ExtractPageContent(ctx, page)
+		assert.NoError(t, err)
+		content, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		watermarked = append(watermarked, bytes.Contains(content, []byte("/Subtype /Watermark")))
+	}
+	return watermarked
+}
+
+func createLogo(t *testing.T, file string) {
+	img := image.NewRGBA(image.Rect(0, 0, 40, 20))
+	for x := 0; x < 40; x++ {
+		img.Set(x, 10, color.RGBA{R: 255, A: 255})
+	}
+	var encoded bytes.Buffer
+	assert.NoError(t, png.Encode(&encoded, img))
+	assert.NoError(t, os.WriteFile(file, encoded.Bytes(), 0644))
+}
+
+func TestWatermarkPdf(t *testing.T) {
+	text := DefaultWatermark()
+	text.Text = "CONFIDENTIAL"
+
+	logo := DefaultWatermark()
+	logo.Image = "logo.png"
+	logo.Underlay = true
+
+	ranged := text
+	ranged.Pages = "2-"
+
+	tests := []struct {
+		name        string
+		watermark   Watermark
+		prefix      string
+		output      string
+		watermarked []bool
+		expectedErr bool
+	}{
+		{
+			name:        "Text on every page",
+			watermark:   text,
+			prefix:      "stamped-",
+			output:      "stamped-three.pdf",
+			watermarked: []bool{true, true, true},
+		},
+		{
+			name:        "Image below the content",
+			watermark:   logo,
+			prefix:      "stamped-",
+			output:      "stamped-three.pdf",
+			watermarked: []bool{true, true, true},
+		},
+		{
+			name:        "Text on a page range",
+			watermark:   ranged,
+			prefix:      "stamped-",
+			output:      "stamped-three.pdf",
+			watermarked: []bool{false, true, true},
+		},
+		{
+			name:        "Page range out of bounds",
+			watermark:   Watermark{Text: "CONFIDENTIAL", Font: "Helvetica", Size: 48, Color: "red", Position: "c", Scale: 0.5, Pages: "4"},
+			prefix:      "stamped-",
+			expectedErr: true,
+		},
+		{
+			name:        "Missing image",
+			watermark:   Watermark{Image: "missing.png", Position: "c", Scale: 0.5},
+			prefix:      "stamped-",
+			expectedErr: true,
+		},
+		{
+			name:        "Overwriting the original needs in place",
+			watermark:   text,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+			createMultiPagePDF(t, tempDir, "three.pdf", 3)
+			createLogo(t, "logo.png")
+
+			output, err := NewPDFProcessor(watermark).WatermarkPdf("three.pdf", tempDir, tt.prefix, tt.watermark)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.output, output)
+			assert.Equal(t, tt.watermarked, watermarkedPages(t, output, nil))
+		})
+	}
+}
+
+func TestEncryptPdfWithWatermark(t *testing.T) {
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+	createMultiPagePDF(t, tempDir, "three.pdf", 3)
+
+	wm := DefaultWatermark()
+	wm.Text = "CONFIDENTIAL"
+	wm.Pages = "1"
+
+	p := NewPDFProcessor(encrypt)
+	p.SetWatermark(wm)
+	output, err := p.EncryptPdf("three.pdf", tempDir, "password", "encrypted-")
+	assert.NoError(t, err)
+
+	assert.Equal(t, []bool{true, false, false}, watermarkedPages(t, output, decryptionConfig("password")))
+}
+
+func TestWatermarkCheck(t *testing.T) {
+	valid := DefaultWatermark()
+	valid.Text = "CONFIDENTIAL"
+
+	tests := []struct {
+		name        string
+		change      func(wm *Watermark)
+		expectedErr string
+	}{
+		{name: "Default watermark", change: func(wm *Watermark) {}},
+		{name: "Color code", change: func(wm *Watermark) { wm.Color = "#ff0000" }},
+		{name: "Unknown font", change: func(wm *Watermark) { wm.Font = "Comic" }, expectedErr: "unknown font Comic"},
+		{name: "No font size", change: func(wm *Watermark) { wm.Size = 0 }, expectedErr: "the font size must be more than 0"},
+		{name: "Invalid color", change: func(wm *Watermark) { wm.Color = "#ff" }, expectedErr: "invalid color #ff"},
+		{name: "Opacity above 1", change: func(wm *Watermark) { wm.Opacity = 1.5 }, expectedErr: "the opacity must be between 0 and 1"},
+		{name: "Rotation out of range", change: func(wm *Watermark) { wm.Rotation = 270 }, expectedErr: "the rotation must be between -180 and 180 degrees"},
+		{name: "Unknown position", change: func(wm *Watermark) { wm.Position = "full" }, expectedErr: "unknown position full"},
+		{name: "Scale above 1", change: func(wm *Watermark) { wm.Scale = 2 }, expectedErr: "the scale must be more than 0 and at most 1"},
+		{name: "Malformed page range", change: func(wm *Watermark) { wm.Pages = "1-a" }, expectedErr: "invalid page range"},
+		{name: "Font of an image", change: func(wm *Watermark) { wm.Text, wm.Image, wm.Font = "", "logo.png", "Comic" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wm := valid
+			tt.change(&wm)
+
+			err := wm.Check()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
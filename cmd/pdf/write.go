@@ -0,0 +1,136 @@
+package pdf
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+)
+
+type OutputExistsError struct {
+	Path    string
+	InPlace bool
+}
+
+func (e *OutputExistsError) Error() string {
+	if e.InPlace {
+		return fmt.Sprintf("%s would be overwritten, use --in-place to overwrite the original PDF files", e.Path)
+	}
+	return fmt.Sprintf("%s already exists, use --force to overwrite it", e.Path)
+}
+
+func IsOutputExists(err error) bool {
+	var existsErr *OutputExistsError
+	return errors.As(err, &existsErr)
+}
+
+func (p *PDFProcessor) SetOverwrite(force, inPlace bool) {
+	p.force = force
+	p.inPlace = inPlace
+}
+
+func (p *PDFProcessor) Force() *PDFProcessor {
+	c := *p
+	c.force = true
+	return &c
+}
+
+func (p *PDFProcessor) SetBackup(backup, created func(path string) error) {
+	p.backup = backup
+	p.created = created
+}
+
+func (p *PDFProcessor) WithoutBackup() *PDFProcessor {
+	c := *p
+	c.backup = nil
+	c.created = nil
+	return &c
+}
+
+func (p *PDFProcessor) checkOverwrite(input, output string) error {
+	outputInfo, err := os.Stat(output)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if p.force {
+		return nil
+	}
+
+	if input != "" {
+		if inputInfo, err := os.Stat(input); err == nil && os.SameFile(inputInfo, outputInfo) {
+			if p.inPlace {
+				return nil
+			}
+			return &OutputExistsError{Path: output, InPlace: true}
+		}
+	}
+	return &OutputExistsError{Path: output}
+}
+
+// writeFile renames a validated temporary file into place, so output is never
+// left half written.
+func (p *PDFProcessor) writeFile(input, output string, conf *model.Configuration, write func(w io.Writer) error) error {
+	if err := p.checkOverwrite(input, output); err != nil {
+		return err
+	}
+
+	mode := fs.FileMode(0600)
+	info, err := os.Stat(output)
+	exists := err == nil
+	if exists {
+		mode = info.Mode().Perm()
+	}
+
+	if p.subdir != "" {
+		if err := os.MkdirAll(filepath.Dir(output), 0750); err != nil {
+			return err
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(output), "."+filepath.Base(output)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	// the rename leaves nothing to remove when everything went well
+	defer os.Remove(tmp.Name())
+
+	if err := write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := api.ValidateFile(tmp.Name(), conf); err != nil {
+		return invalidPDF(err)
+	}
+
+	if exists && p.backup != nil {
+		if err := p.backup(output); err != nil {
+			return err
+		}
+	}
+	if !exists && p.created != nil {
+		if err := p.created(output); err != nil {
+			return err
+		}
+	}
+	return os.Rename(tmp.Name(), output)
+}
@@ -0,0 +1,124 @@
+package pdf
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckOverwrite(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestFiles(t, tempDir, []string{"input.pdf", "existing.pdf"})
+	input := filepath.Join(tempDir, "input.pdf")
+
+	tests := []struct {
+		name     string
+		force    bool
+		inPlace  bool
+		output   string
+		expected string
+	}{
+		{
+			name:   "new file",
+			output: filepath.Join(tempDir, "new.pdf"),
+		},
+		{
+			name:     "existing file",
+			output:   filepath.Join(tempDir, "existing.pdf"),
+			expected: "already exists, use --force to overwrite it",
+		},
+		{
+			name:   "existing file with force",
+			force:  true,
+			output: filepath.Join(tempDir, "existing.pdf"),
+		},
+		{
+			name:     "existing file in place",
+			inPlace:  true,
+			output:   filepath.Join(tempDir, "existing.pdf"),
+			expected: "already exists, use --force to overwrite it",
+		},
+		{
+			name:     "original file",
+			output:   input,
+			expected: "would be overwritten, use --in-place to overwrite the original PDF files",
+		},
+		{
+			name:    "original file in place",
+			inPlace: true,
+			output:  input,
+		},
+		{
+			name:   "original file with force",
+			force:  true,
+			output: input,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := NewPDFProcessor(encrypt)
+			processor.SetOverwrite(tt.force, tt.inPlace)
+
+			err := processor.checkOverwrite(input, tt.output)
+			if tt.expected == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expected)
+			assert.True(t, IsOutputExists(err), "Expected an output exists error")
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestFiles(t, tempDir, []string{"test.pdf"})
+	output := filepath.Join(tempDir, "test.pdf")
+
+	original, err := os.ReadFile(output)
+	assert.NoError(t, err)
+
+	processor := NewPDFProcessor(encrypt)
+	processor.SetOverwrite(true, false)
+
+	// a failed write leaves the existing file as it was
+	err = processor.writeFile("", output, nil, func(w io.Writer) error {
+		if _, err := w.Write([]byte("%PDF-1.4\n")); err != nil {
+			return err
+		}
+		return errors.New("write failed")
+	})
+	assert.EqualError(t, err, "write failed")
+
+	content, err := os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Equal(t, original, content, "Expected the existing file to be untouched")
+
+	// so does a write that isn't a valid PDF
+	err = processor.writeFile("", output, nil, func(w io.Writer) error {
+		_, err := w.Write([]byte("not a pdf"))
+		return err
+	})
+	assert.ErrorIs(t, err, ErrInvalidPDF)
+
+	content, err = os.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Equal(t, original, content, "Expected the existing file to be untouched")
+
+	err = processor.writeFile("", output, nil, func(w io.Writer) error {
+		_, err := w.Write(original)
+		return err
+	})
+	assert.NoError(t, err)
+
+	entries, err := os.ReadDir(tempDir)
+	assert.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotEqual(t, ".tmp", filepath.Ext(entry.Name()), "Expected the temporary files to be removed")
+	}
+}
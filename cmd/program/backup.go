@@ -0,0 +1,126 @@
+package program
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/gmskazi/pdfmc/cmd/backup"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/styles"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+type UndoFlags struct {
+	undoID string
+	list   bool
+}
+
+func openBackupStore() (*backup.Store, error) {
+	dir := os.Getenv("PDFMC_BACKUP_DIR")
+	if dir == "" {
+		var err error
+		dir, err = backup.DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keep := backup.DefaultKeep
+	if value := os.Getenv("PDFMC_BACKUP_KEEP"); value != "" {
+		var err error
+		keep, err = strconv.Atoi(value)
+		if err != nil || keep < 0 {
+			return nil, utils.NewUsageError("PDFMC_BACKUP_KEEP must be a number of operations, got: %s", value)
+		}
+	}
+
+	maxAge := backup.DefaultMaxAge
+	if value := os.Getenv("PDFMC_BACKUP_MAX_AGE"); value != "" {
+		var err error
+		maxAge, err = time.ParseDuration(value)
+		if err != nil || maxAge < 0 {
+			return nil, utils.NewUsageError("PDFMC_BACKUP_MAX_AGE must be a duration like 720h, got: %s", value)
+		}
+	}
+
+	return backup.NewStore(dir, keep, maxAge), nil
+}
+
+func (p *Program) setupBackup(pdfProcessor *pdf.PDFProcessor) error {
+	if !p.backup {
+		return nil
+	}
+
+	store, err := openBackupStore()
+	if err != nil {
+		return err
+	}
+	operation := store.Begin(p.logo)
+	pdfProcessor.SetBackup(operation.Save, operation.Created)
+	return nil
+}
+
+func (p *Program) ExecuteUndo() error {
+	store, err := openBackupStore()
+	if err != nil {
+		return err
+	}
+
+	if p.list {
+		return p.listBackups(store)
+	}
+
+	var operation *backup.Operation
+	if p.undoID != "" {
+		operation, err = store.Get(p.undoID)
+	} else {
+		operation, err = store.Last()
+	}
+	if err != nil {
+		return err
+	}
+
+	restored, err := store.Restore(operation)
+	for _, file := range restored {
+		complete := fmt.Sprintf("PDF file restored successfully to: %s", file.Original)
+		if file.Created {
+			complete = fmt.Sprintf("PDF file removed successfully: %s", file.Original)
+		}
+		p.cmd.Println(styles.SelectedStyle.Render(complete))
+	}
+	return err
+}
+
+func (p *Program) listBackups(store *backup.Store) error {
+	operations, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	if len(operations) == 0 {
+		p.cmd.Println(styles.InfoStyle.Render("No backups found."))
+		return nil
+	}
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color(styles.InfoColor))).
+		Headers("ID", "COMMAND", "TIME", "FILES").
+		StyleFunc(func(row, col int) lipgloss.Style {
+			if row == table.HeaderRow {
+				return styles.InfoStyle.PaddingRight(1)
+			}
+			return styles.SelectedStyle.PaddingRight(1)
+		})
+
+	for _, operation := range operations {
+		t.Row(operation.ID, operation.Op, operation.Time.Format(time.DateTime), strconv.Itoa(len(operation.Files)))
+	}
+
+	p.cmd.Println(t.Render())
+	return nil
+}
@@ -0,0 +1,281 @@
+package program
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/styles"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+type BatchFlags struct {
+	keepGoing bool
+	jobs      int
+}
+
+type fileResult struct {
+	index    int
+	file     string
+	outputs  []string
+	err      error
+	duration time.Duration
+	// note is shown after the outputs, like the change in size
+	note string
+}
+
+type BatchError struct {
+	Op     string
+	Failed int
+	Total  int
+	errs   []error
+}
+
+func newBatchError(op string, results []fileResult) *BatchError {
+	e := &BatchError{Op: op, Total: len(results)}
+	for _, result := range results {
+		if result.err != nil {
+			e.Failed++
+			e.errs = append(e.errs, result.err)
+		}
+	}
+	return e
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("failed to %s %d of %d PDF files", e.Op, e.Failed, e.Total)
+}
+
+func (e *BatchError) Unwrap() []error {
+	return e.errs
+}
+
+func (p *Program) checkBatchFlags() error {
+	if p.jobs < 1 {
+		return utils.NewUsageError("the --jobs flag must be at least 1")
+	}
+	return nil
+}
+
+// runBatch reports the results in the order of files, on Ctrl+C the files
+// that were started still finish.
+func (p *Program) runBatch(files []string, process func(index int, file string) fileResult, report func(result *fileResult) error) ([]fileResult, error) {
+	var (
+		results []fileResult
+		wg      sync.WaitGroup
+	)
+
+	interrupted, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stopSignals()
+	ctx, cancel := context.WithCancel(interrupted)
+	defer cancel()
+
+	// every file gets its own buffered channel so the workers never block
+	// on a result that can't be reported yet
+	done := make([]chan fileResult, len(files))
+	for i := range done {
+		done[i] = make(chan fileResult, 1)
+	}
+
+	// the files are handed out in order, so the files that were started
+	// are always the first ones
+	next := make(chan int)
+	go func() {
+		defer close(next)
+		for i := range files {
+			select {
+			case next <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for range min(max(p.jobs, 1), len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				result := process(i+1, files[i])
+				result.index = i + 1
+				done[i] <- result
+			}
+		}()
+	}
+
+	reported := 0
+	reportNext := func(result fileResult) error {
+		reported++
+		err := report(&result)
+		results = append(results, result)
+		return err
+	}
+
+loop:
+	for reported < len(files) {
+		select {
+		case result := <-done[reported]:
+			if err := reportNext(result); err != nil {
+				cancel()
+				wg.Wait()
+				return results, err
+			}
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	wg.Wait()
+
+	// report the files that were still being processed on Ctrl+C
+	for reported < len(files) && len(done[reported]) > 0 {
+		if err := reportNext(<-done[reported]); err != nil {
+			return results, err
+		}
+	}
+
+	if reported < len(files) {
+		return results, fmt.Errorf("%w, %d of %d PDF files weren't processed", utils.ErrCanceled, len(files)-reported, len(files))
+	}
+	return results, nil
+}
+
+type batchStep struct {
+	// e.g. "rotate" and "rotated"
+	verb    string
+	done    string
+	process func(pdfProcessor *pdf.PDFProcessor, dir, file string) (outputs []string, note string, err error)
+}
+
+func (p *Program) executeBatch(step batchStep) error {
+	if err := p.checkBatchFlags(); err != nil {
+		return err
+	}
+
+	f, err := p.newFileUtils()
+	if err != nil {
+		return err
+	}
+	pdfProcessor := pdf.NewPDFProcessor(p.logo)
+	if err := p.setupOutput(pdfProcessor); err != nil {
+		return err
+	}
+
+	selectedPdfs, dir, saveDir, err := p.selectPdfs(f)
+	if err != nil || len(selectedPdfs) == 0 {
+		return err
+	}
+
+	return p.processBatch(pdfProcessor, selectedPdfs, dir, saveDir, f.Interactive, step)
+}
+
+func (p *Program) processBatch(pdfProcessor *pdf.PDFProcessor, selectedPdfs []string, dir, saveDir string, interactive bool, step batchStep) error {
+	process := func(pdfProcessor *pdf.PDFProcessor, result *fileResult) {
+		start := time.Now()
+		fileDir, filePdf := p.splitDir(dir, result.file)
+		result.outputs, result.note, result.err = step.process(pdfProcessor.WithIndex(result.index), fileDir, filePdf)
+		result.duration += time.Since(start)
+	}
+
+	results, err := p.runBatch(selectedPdfs, func(index int, file string) fileResult {
+		result := fileResult{index: index, file: file}
+		process(pdfProcessor, &result)
+		return result
+	}, func(result *fileResult) error {
+		overwrite, promptErr := p.confirmOverwrite(result.err, interactive)
+		if promptErr != nil {
+			return promptErr
+		}
+		if overwrite {
+			process(pdfProcessor.Force(), result)
+		}
+
+		if result.err != nil {
+			// the files the user chose not to overwrite are skipped
+			if !p.keepGoing && !(pdf.IsOutputExists(result.err) && interactive) {
+				return result.err
+			}
+			p.cmd.PrintErrln(styles.ErrorStyle.Render(fmt.Sprintf("%s: %s", result.file, result.err.Error())))
+			return nil
+		}
+		for _, output := range result.outputs {
+			complete := fmt.Sprintf("PDF file %s successfully to: %s", step.done, displayPath(saveDir, output))
+			if result.note != "" {
+				complete += fmt.Sprintf(" (%s)", result.note)
+			}
+			p.cmd.Println(styles.SelectedStyle.Render(complete))
+		}
+		return nil
+	})
+
+	if p.keepGoing && len(results) > 0 {
+		p.printSummary(results, saveDir)
+	}
+	if err != nil {
+		return err
+	}
+	if countFailed(results) > 0 {
+		return newBatchError(step.verb, results)
+	}
+	return nil
+}
+
+func countFailed(results []fileResult) int {
+	failed := 0
+	for _, result := range results {
+		if result.err != nil {
+			failed++
+		}
+	}
+	return failed
+}
+
+func (p *Program) printSummary(results []fileResult, saveDir string) {
+	failed := countFailed(results)
+
+	t := table.New().
+		Border(lipgloss.NormalBorder()).
+		BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color(styles.InfoColor))).
+		Headers("FILE", "STATUS", "OUTPUT / ERROR", "DURATION").
+		StyleFunc(func(row, col int) lipgloss.Style {
+			switch {
+			case row == table.HeaderRow:
+				return styles.InfoStyle.PaddingRight(1)
+			case results[row].err != nil:
+				return styles.ErrorStyle.PaddingRight(1)
+			default:
+				return styles.SelectedStyle.PaddingRight(1)
+			}
+		})
+
+	for _, result := range results {
+		outputs := make([]string, len(result.outputs))
+		for i, output := range result.outputs {
+			outputs[i] = displayPath(saveDir, output)
+		}
+		status, detail := "ok", strings.Join(outputs, "\n")
+		if result.note != "" {
+			detail += " (" + result.note + ")"
+		}
+		if result.err != nil {
+			status, detail = "failed", result.err.Error()
+		}
+		t.Row(result.file, status, detail, result.duration.Round(time.Millisecond).String())
+	}
+
+	p.cmd.Println()
+	p.cmd.Println(t.Render())
+
+	summary := fmt.Sprintf("%d succeeded, %d failed", len(results)-failed, failed)
+	if failed > 0 {
+		p.cmd.Println(styles.ErrorStyle.Render(summary))
+	} else {
+		p.cmd.Println(styles.InfoStyle.Render(summary))
+	}
+}
@@ -0,0 +1,60 @@
+package program
+
+import (
+	"github.com/gmskazi/pdfmc/cmd/styles"
+	"github.com/gmskazi/pdfmc/cmd/ui/multiSelect"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+type FileFlags struct {
+	recursive bool
+	include   []string
+	exclude   []string
+	files     *utils.FileUtils
+}
+
+func (p *Program) newFileUtils() (*utils.FileUtils, error) {
+	f := utils.NewFileUtils(p.args)
+	if err := f.SetFilters(p.recursive, p.include, p.exclude); err != nil {
+		return nil, err
+	}
+	if p.logo == "merge" {
+		f.AllowPageSelections()
+	}
+	p.files = f
+	return f, nil
+}
+
+func (p *Program) splitDir(dir, file string) (string, string) {
+	if p.files == nil {
+		return dir, file
+	}
+	return p.files.SplitDir(dir, file)
+}
+
+func (p *Program) selectPdfs(f *utils.FileUtils) (selectedPdfs []string, dir, saveDir string, err error) {
+	pdfs, dir, err := f.CheckProvidedArgs()
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	selectedPdfs = pdfs
+	if f.Interactive {
+		var quit bool
+		selectedPdfs, quit, err = multiSelect.MultiSelectInteractive(pdfs, dir, p.logo, f.GetPdfFilesFromDir)
+		if err != nil || quit {
+			return nil, "", "", err
+		}
+
+		if len(selectedPdfs) == 0 {
+			p.cmd.Println(styles.InfoStyle.Render("No PDFs were selected. Exiting."))
+			return nil, "", "", nil
+		}
+	}
+
+	saveDir, err = f.GetCurrentWorkingDir()
+	if err != nil {
+		return nil, "", "", err
+	}
+	return selectedPdfs, dir, saveDir, nil
+}
@@ -0,0 +1,72 @@
+package program
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+type OptimizeFlags struct {
+	optimize bool
+	dpi      int
+}
+
+func (p *Program) checkOptimizeFlags() error {
+	if p.dpi < 0 {
+		return utils.NewUsageError("the --dpi flag can't be negative")
+	}
+	// merge only optimizes the merged PDF with --optimize
+	if p.logo == "merge" && p.dpi > 0 && !p.optimize {
+		return utils.NewUsageError("the --dpi flag needs the --optimize flag")
+	}
+	return nil
+}
+
+func (p *Program) optimization() pdf.Optimization {
+	return pdf.Optimization{DPI: p.dpi}
+}
+
+func sizeChange(before, after int64) string {
+	change := "±0%"
+	if before > 0 && after != before {
+		change = fmt.Sprintf("%+d%%", (after-before)*100/before)
+	}
+	return fmt.Sprintf("%s → %s, %s", utils.FormatSize(before), utils.FormatSize(after), change)
+}
+
+func fileSize(file string) int64 {
+	info, err := os.Stat(file)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// optimizePdf reads the size first, the PDF may be overwritten.
+func (p *Program) optimizePdf(pdfProcessor *pdf.PDFProcessor, dir, file, prefix string) (string, string, error) {
+	before := fileSize(filepath.Join(dir, file))
+
+	optimizedPdf, err := pdfProcessor.OptimizePdf(file, dir, prefix, p.optimization())
+	if err != nil {
+		return "", "", err
+	}
+	return optimizedPdf, sizeChange(before, fileSize(optimizedPdf)), nil
+}
+
+func (p *Program) ExecuteOptimize() error {
+	if err := p.checkOptimizeFlags(); err != nil {
+		return err
+	}
+
+	return p.executeBatch(batchStep{
+		verb: "optimize",
+		done: "optimized",
+		process: func(pdfProcessor *pdf.PDFProcessor, dir, file string) ([]string, string, error) {
+			optimizedPdf, note, err := p.optimizePdf(pdfProcessor, dir, file, p.name)
+			return []string{optimizedPdf}, note, err
+		},
+	})
+}
@@ -0,0 +1,63 @@
+package program
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/ui/confirm"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+type OutputFlags struct {
+	outputDir    string
+	nameTemplate string
+	force        bool
+	inPlace      bool
+	backup       bool
+	// overwriteAll is set once the user answered "all" to an overwrite prompt
+	overwriteAll bool
+}
+
+func (p *Program) setupOutput(pdfProcessor *pdf.PDFProcessor) error {
+	if err := pdf.CheckNameTemplate(p.nameTemplate); err != nil {
+		return &utils.UsageError{Err: err}
+	}
+	// every part of a split PDF needs a name of its own
+	if p.logo == "split" && p.nameTemplate != "" && !strings.Contains(p.nameTemplate, "{pages}") {
+		return utils.NewUsageError("the --name-template flag needs the {pages} token to split PDF files")
+	}
+	pdfProcessor.SetOverwrite(p.force, p.inPlace)
+	if err := p.setupBackup(pdfProcessor); err != nil {
+		return err
+	}
+	return pdfProcessor.SetOutput(p.outputDir, p.nameTemplate)
+}
+
+func (p *Program) confirmOverwrite(err error, interactive bool) (bool, error) {
+	var existsErr *pdf.OutputExistsError
+	if !interactive || !errors.As(err, &existsErr) {
+		return false, nil
+	}
+	if p.overwriteAll {
+		return true, nil
+	}
+
+	answer, quit, err := confirm.ConfirmInteractive(fmt.Sprintf("%s already exists, overwrite it?", existsErr.Path))
+	if err != nil || quit {
+		return false, err
+	}
+	if answer == confirm.All {
+		p.overwriteAll = true
+	}
+	return answer != confirm.No, nil
+}
+
+func displayPath(saveDir, output string) string {
+	if filepath.IsAbs(output) {
+		return output
+	}
+	return filepath.Join(saveDir, output)
+}
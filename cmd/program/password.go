@@ -0,0 +1,153 @@
+package program
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+type PasswordFlags struct {
+	pwordFile  string
+	pwordStdin bool
+	pwordEnv   string
+	pwordMap   string
+	resolved   bool
+}
+
+func (p *Program) resolvePassword() error {
+	if p.resolved {
+		return nil
+	}
+	p.resolved = true
+
+	var sources []string
+	if p.pword != "" {
+		sources = append(sources, "--password")
+	}
+	if p.pwordFile != "" {
+		sources = append(sources, "--password-file")
+	}
+	if p.pwordStdin {
+		sources = append(sources, "--password-stdin")
+	}
+	if p.pwordEnv != "" {
+		sources = append(sources, "--password-env")
+	}
+
+	if len(sources) > 1 {
+		return utils.NewUsageError("please provide only one password source, got: %s", strings.Join(sources, ", "))
+	}
+
+	switch {
+	case p.pwordFile != "":
+		content, err := os.ReadFile(filepath.Clean(p.pwordFile))
+		if err != nil {
+			return fmt.Errorf("failed to read the password file: %w", err)
+		}
+		p.pword = trimPassword(string(content))
+
+	case p.pwordStdin:
+		line, err := bufio.NewReader(p.cmd.InOrStdin()).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("failed to read the password from stdin: %w", err)
+		}
+		p.pword = trimPassword(line)
+
+	case p.pwordEnv != "":
+		pword, ok := os.LookupEnv(p.pwordEnv)
+		if !ok {
+			return fmt.Errorf("the environment variable %s is not set", p.pwordEnv)
+		}
+		p.pword = pword
+	default:
+		return nil
+	}
+
+	if p.pword == "" {
+		return fmt.Errorf("the password from %s is empty", sources[0])
+	}
+	return nil
+}
+
+func trimPassword(pword string) string {
+	return strings.TrimRight(pword, "\r\n")
+}
+
+type passwordMapping struct {
+	pattern string
+	pword   string
+}
+
+func loadPasswordMap(path string) ([]passwordMapping, error) {
+	var mappings []passwordMapping
+
+	content, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the password map: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		var entries map[string]string
+		if err := json.Unmarshal(content, &entries); err != nil {
+			return nil, fmt.Errorf("failed to parse the password map: %w", err)
+		}
+
+		for pattern, pword := range entries {
+			mappings = append(mappings, passwordMapping{pattern: pattern, pword: pword})
+		}
+
+		// JSON objects are unordered, so exact file names win over globs and
+		// longer globs over shorter ones
+		sort.Slice(mappings, func(i, j int) bool {
+			iGlob, jGlob := isGlob(mappings[i].pattern), isGlob(mappings[j].pattern)
+			if iGlob != jGlob {
+				return !iGlob
+			}
+			if len(mappings[i].pattern) != len(mappings[j].pattern) {
+				return len(mappings[i].pattern) > len(mappings[j].pattern)
+			}
+			return mappings[i].pattern < mappings[j].pattern
+		})
+		return mappings, nil
+	}
+
+	reader := csv.NewReader(bytes.NewReader(content))
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the password map: %w", err)
+	}
+
+	for _, record := range records {
+		mappings = append(mappings, passwordMapping{pattern: record[0], pword: record[1]})
+	}
+	return mappings, nil
+}
+
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+func passwordFor(mappings []passwordMapping, file string) (string, bool) {
+	for _, m := range mappings {
+		for _, name := range []string{file, filepath.Base(file)} {
+			if ok, _ := filepath.Match(m.pattern, name); ok {
+				return m.pword, true
+			}
+		}
+	}
+	return "", false
+}
@@ -1,8 +1,8 @@
 package program
 
 import (
-	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gmskazi/pdfmc/cmd/pdf"
 	"github.com/gmskazi/pdfmc/cmd/styles"
@@ -21,26 +21,134 @@ type Program struct {
 	name  string
 	pword string
 	MergeFlags
+	SplitFlags
+	RotateFlags
+	OptimizeFlags
+	WatermarkFlags
+	EncryptFlags
+	ChangePasswordFlags
+	PasswordFlags
+	BatchFlags
+	OutputFlags
+	UndoFlags
+	FileFlags
 }
 
 type MergeFlags struct {
-	reorder bool
-	encrypt bool
+	reorder  bool
+	pageMode bool
+	encrypt  bool
+	sortBy   string
+	reverse  bool
+}
+
+type EncryptFlags struct {
+	userPword   string
+	ownerPword  string
+	permissions string
+	algorithm   string
+	keyLength   int
+}
+
+type ChangePasswordFlags struct {
+	oldPword string
+	newPword string
+}
+
+type SplitFlags struct {
+	every     int
+	ranges    string
+	bookmarks bool
 }
 
 func NewProgram(cmd *cobra.Command, args []string, logo string) *Program {
 	mergeFlags := MergeFlags{
-		reorder: getFlagBoolValue(cmd, "order"),
-		encrypt: getFlagBoolValue(cmd, "encrypt"),
+		reorder:  getFlagBoolValue(cmd, "order"),
+		pageMode: getFlagBoolValue(cmd, "page-mode"),
+		encrypt:  getFlagBoolValue(cmd, "encrypt"),
+		sortBy:   getFlagValue(cmd.Flag("sort")),
+		reverse:  getFlagBoolValue(cmd, "reverse"),
+	}
+
+	splitFlags := SplitFlags{
+		every:     getFlagIntValue(cmd, "every"),
+		ranges:    getFlagValue(cmd.Flag("ranges")),
+		bookmarks: getFlagBoolValue(cmd, "bookmarks"),
+	}
+
+	rotateFlags := RotateFlags{
+		degrees:      getFlagIntValue(cmd, "degrees"),
+		rotatePages:  getFlagValue(cmd.Flag("pages")),
+		autoPortrait: getFlagBoolValue(cmd, "auto-portrait"),
+	}
+
+	optimizeFlags := OptimizeFlags{
+		optimize: getFlagBoolValue(cmd, "optimize"),
+		dpi:      getFlagIntValue(cmd, "dpi"),
+	}
+
+	encryptFlags := EncryptFlags{
+		userPword:   getFlagValue(cmd.Flag("user-password")),
+		ownerPword:  getFlagValue(cmd.Flag("owner-password")),
+		permissions: getFlagValue(cmd.Flag("permissions")),
+		algorithm:   getFlagValue(cmd.Flag("algorithm")),
+		keyLength:   getFlagIntValue(cmd, "key-length"),
+	}
+
+	changePasswordFlags := ChangePasswordFlags{
+		oldPword: getFlagValue(cmd.Flag("old-password")),
+		newPword: getFlagValue(cmd.Flag("new-password")),
+	}
+
+	passwordFlags := PasswordFlags{
+		pwordFile:  getFlagValue(cmd.Flag("password-file")),
+		pwordStdin: getFlagBoolValue(cmd, "password-stdin"),
+		pwordEnv:   getFlagValue(cmd.Flag("password-env")),
+		pwordMap:   getFlagValue(cmd.Flag("password-map")),
+	}
+
+	batchFlags := BatchFlags{
+		keepGoing: getFlagBoolValue(cmd, "keep-going"),
+		jobs:      getFlagIntValue(cmd, "jobs"),
+	}
+
+	outputFlags := OutputFlags{
+		outputDir:    getFlagValue(cmd.Flag("output-dir")),
+		nameTemplate: getFlagValue(cmd.Flag("name-template")),
+		force:        getFlagBoolValue(cmd, "force"),
+		inPlace:      getFlagBoolValue(cmd, "in-place"),
+		backup:       getFlagBoolValue(cmd, "backup"),
+	}
+
+	fileFlags := FileFlags{
+		recursive: getFlagBoolValue(cmd, "recursive"),
+		include:   getFlagStringSliceValue(cmd, "include"),
+		exclude:   getFlagStringSliceValue(cmd, "exclude"),
+	}
+
+	undoFlags := UndoFlags{
+		undoID: getFlagValue(cmd.Flag("id")),
+		list:   getFlagBoolValue(cmd, "list"),
 	}
 
 	return &Program{
-		cmd:        cmd,
-		args:       args,
-		logo:       logo,
-		name:       getFlagValue(cmd.Flag("name")),
-		pword:      getFlagValue(cmd.Flag("password")),
-		MergeFlags: mergeFlags,
+		cmd:                 cmd,
+		args:                args,
+		logo:                logo,
+		name:                getFlagValue(cmd.Flag("name")),
+		pword:               getFlagValue(cmd.Flag("password")),
+		MergeFlags:          mergeFlags,
+		SplitFlags:          splitFlags,
+		RotateFlags:         rotateFlags,
+		OptimizeFlags:       optimizeFlags,
+		WatermarkFlags:      newWatermarkFlags(cmd),
+		EncryptFlags:        encryptFlags,
+		ChangePasswordFlags: changePasswordFlags,
+		PasswordFlags:       passwordFlags,
+		BatchFlags:          batchFlags,
+		OutputFlags:         outputFlags,
+		UndoFlags:           undoFlags,
+		FileFlags:           fileFlags,
 	}
 }
 
@@ -59,9 +167,30 @@ func getFlagBoolValue(cmd *cobra.Command, flagname string) bool {
 	return value
 }
 
+func getFlagStringSliceValue(cmd *cobra.Command, flagname string) []string {
+	value, err := cmd.Flags().GetStringSlice(flagname)
+	if err != nil {
+		return nil
+	}
+	return value
+}
+
+func getFlagIntValue(cmd *cobra.Command, flagname string) int {
+	value, err := cmd.Flags().GetInt(flagname)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
 func (p *Program) getPassword() error {
-	// check and update the password
-	if p.pword == "" {
+	if err := p.resolvePassword(); err != nil {
+		return err
+	}
+
+	// check and update the password, an owner password on its own is enough
+	// to encrypt PDFs that open without a password
+	if p.pword == "" && p.ownerPword == "" {
 		newPword, quit, err := textInputs.TextinputInteractive()
 		if err != nil || quit {
 			return err
@@ -72,79 +201,173 @@ func (p *Program) getPassword() error {
 	return nil
 }
 
-func (p *Program) processEncryptPDFs(pdfProcessor *pdf.PDFProcessor, selectedPdfs []string, dir, saveDir, pword string) error {
-	for _, pdf := range selectedPdfs {
-		if p.logo == "merge" {
-			encryptedPdf, err := pdfProcessor.EncryptPdf(pdf, dir, pword, "")
-			if err != nil {
-				return err
-			}
-			complete := fmt.Sprintf("PDF files merged and encrypted successfully to: %s/%s", saveDir, encryptedPdf)
-			p.cmd.Println(styles.SelectedStyle.Render(complete))
-		} else {
-			encryptedPdf, err := pdfProcessor.EncryptPdf(pdf, dir, pword, p.name)
+func (p *Program) setupEncryption(pdfProcessor *pdf.PDFProcessor) error {
+	if p.userPword != "" {
+		if p.pword != "" {
+			return utils.NewUsageError("please provide either the --password flag or the --user-password flag")
+		}
+		p.pword = p.userPword
+	}
+
+	pdfProcessor.SetOwnerPassword(p.ownerPword)
+
+	if p.algorithm != "" || p.keyLength != 0 {
+		algorithm := p.algorithm
+		if algorithm == "" {
+			algorithm = pdf.AlgorithmAES
+		}
+		if err := pdfProcessor.SetAlgorithm(algorithm, p.keyLength); err != nil {
+			return &utils.UsageError{Err: err}
+		}
+	}
+
+	if p.permissions != "" {
+		permissions, err := pdf.ParsePermissions(p.permissions)
+		if err != nil {
+			return &utils.UsageError{Err: err}
+		}
+		pdfProcessor.SetPermissions(permissions)
+	}
+	return nil
+}
+
+func (p *Program) processEncryptPDFs(pdfProcessor *pdf.PDFProcessor, selectedPdfs []string, dir, saveDir, pword string, interactive bool) error {
+	if p.logo == "merge" {
+		for _, file := range selectedPdfs {
+			encryptedPdf, err := pdfProcessor.EncryptPdf(file, dir, pword, "")
 			if err != nil {
 				return err
 			}
-			complete := fmt.Sprintf("PDF file encrypted successfully to: %s/%s", saveDir, encryptedPdf)
+			complete := fmt.Sprintf("PDF files merged and encrypted successfully to: %s", displayPath(saveDir, encryptedPdf))
 			p.cmd.Println(styles.SelectedStyle.Render(complete))
 		}
+		return nil
 	}
-	return nil
+
+	return p.processBatch(pdfProcessor, selectedPdfs, dir, saveDir, interactive, batchStep{
+		verb: "encrypt",
+		done: "encrypted",
+		process: func(pdfProcessor *pdf.PDFProcessor, dir, file string) ([]string, string, error) {
+			encryptedPdf, err := pdfProcessor.EncryptPdf(file, dir, pword, p.name)
+			return []string{encryptedPdf}, "", err
+		},
+	})
 }
 
-func (p *Program) processDecryptPDFs(pdfProcessor *pdf.PDFProcessor, selectedPdfs []string, dir, saveDir, pword string) error {
-	for _, pdf := range selectedPdfs {
-		encryptedPdf, err := pdfProcessor.DecryptPdf(pdf, dir, pword, p.name)
+func (p *Program) processDecryptPDFs(pdfProcessor *pdf.PDFProcessor, selectedPdfs []string, dir, saveDir, pword string, interactive bool) error {
+	var (
+		mappings []passwordMapping
+		err      error
+	)
+
+	if p.pwordMap != "" {
+		mappings, err = loadPasswordMap(p.pwordMap)
 		if err != nil {
 			return err
 		}
+	}
+
+	filePassword := func(file string) string {
+		if mapped, ok := passwordFor(mappings, file); ok {
+			return mapped
+		}
+		return pword
+	}
+
+	results, err := p.runBatch(selectedPdfs, func(index int, file string) fileResult {
+		start := time.Now()
+		fileDir, filePdf := p.splitDir(dir, file)
+		decryptedPdf, err := pdfProcessor.WithIndex(index).DecryptPdf(filePdf, fileDir, filePassword(file), p.name)
+		return fileResult{file: file, outputs: []string{decryptedPdf}, err: err, duration: time.Since(start)}
+	}, func(result *fileResult) error {
+		// the results are reported one at a time, so the prompts never overlap
+		filePword := filePassword(result.file)
+		fileDir, filePdf := p.splitDir(dir, result.file)
+		if pdf.IsWrongPassword(result.err) && interactive {
+			var (
+				quit      bool
+				promptErr error
+			)
+			filePword, quit, promptErr = textInputs.FilePasswordInteractive(result.file)
+			if promptErr != nil || quit {
+				return promptErr
+			}
+			start := time.Now()
+			decryptedPdf, err := pdfProcessor.WithIndex(result.index).DecryptPdf(filePdf, fileDir, filePword, p.name)
+			result.outputs, result.err = []string{decryptedPdf}, err
+			result.duration += time.Since(start)
+		}
+
+		overwrite, promptErr := p.confirmOverwrite(result.err, interactive)
+		if promptErr != nil {
+			return promptErr
+		}
+		if overwrite {
+			start := time.Now()
+			decryptedPdf, err := pdfProcessor.WithIndex(result.index).Force().DecryptPdf(filePdf, fileDir, filePword, p.name)
+			result.outputs, result.err = []string{decryptedPdf}, err
+			result.duration += time.Since(start)
+		}
+
+		if result.err != nil {
+			// wrong passwords and declined overwrites don't stop the batch, other
+			// errors only with --keep-going
+			skipped := pdf.IsOutputExists(result.err) && interactive
+			if !p.keepGoing && !skipped && !(pdf.IsWrongPassword(result.err) && (interactive || mappings != nil)) {
+				return result.err
+			}
+			p.cmd.PrintErrln(styles.ErrorStyle.Render(fmt.Sprintf("%s: %s", result.file, result.err.Error())))
+			return nil
+		}
 
-		complete := fmt.Sprintf("PDF file decrypted successfully to: %s/%s", saveDir, encryptedPdf)
+		complete := fmt.Sprintf("PDF file decrypted successfully to: %s", displayPath(saveDir, result.outputs[0]))
 		p.cmd.Println(styles.SelectedStyle.Render(complete))
+		return nil
+	})
+
+	if p.keepGoing && len(results) > 0 {
+		p.printSummary(results, saveDir)
+	}
+	if err != nil {
+		return err
+	}
+	if countFailed(results) > 0 {
+		return newBatchError("decrypt", results)
 	}
 	return nil
 }
 
 func (p *Program) ExecuteEncrypt() error {
-	var (
-		selectedPdfs []string
-		quit         bool
-		err          error
-	)
-
-	f := utils.NewFileUtils(p.args)
-	pdfProcessor := pdf.NewPDFProcessor(p.logo)
+	if err := p.checkBatchFlags(); err != nil {
+		return err
+	}
 
-	pdfs, dir, err := f.CheckProvidedArgs()
+	f, err := p.newFileUtils()
 	if err != nil {
 		return err
 	}
+	pdfProcessor := pdf.NewPDFProcessor(p.logo)
+	if err := p.setupOutput(pdfProcessor); err != nil {
+		return err
+	}
 
-	if f.Interactive {
-		selectedPdfs, quit, err = multiSelect.MultiSelectInteractive(pdfs, dir, p.logo)
-		if err != nil || quit {
-			return err
-		}
-
-		if len(selectedPdfs) == 0 {
-			p.cmd.Println(styles.InfoStyle.Render("No PDFs were selected. Exiting."))
-			return err
-		}
-	} else {
-		selectedPdfs = pdfs
+	if err := p.setupEncryption(pdfProcessor); err != nil {
+		return err
+	}
+	if err := p.setupWatermark(pdfProcessor); err != nil {
+		return err
 	}
 
-	if err := p.getPassword(); err != nil {
+	selectedPdfs, dir, saveDir, err := p.selectPdfs(f)
+	if err != nil || len(selectedPdfs) == 0 {
 		return err
 	}
 
-	saveDir, err := f.GetCurrentWorkingDir()
-	if err != nil {
+	if err := p.getPassword(); err != nil {
 		return err
 	}
 
-	if err := p.processEncryptPDFs(pdfProcessor, selectedPdfs, dir, saveDir, p.pword); err != nil {
+	if err := p.processEncryptPDFs(pdfProcessor, selectedPdfs, dir, saveDir, p.pword, f.Interactive); err != nil {
 		return err
 	}
 	return nil
@@ -157,10 +380,42 @@ func (p *Program) ExecuteMerge() error {
 		err          error
 	)
 
-	if p.encrypt && p.pword != "" {
-		return errors.New("please provide either the --password flag or use the --encrypt flag for interactive encryption")
+	if err := p.resolvePassword(); err != nil {
+		return err
+	}
+
+	if p.encrypt && (p.pword != "" || p.userPword != "") {
+		return utils.NewUsageError("please provide either the --password flag or use the --encrypt flag for interactive encryption")
+	}
+
+	if err := checkSortOrder(p.sortBy); err != nil {
+		return err
+	}
+	if err := p.checkOptimizeFlags(); err != nil {
+		return err
+	}
+	if p.hasWatermark() {
+		if err := p.checkWatermarkFlags("watermark", "watermark-image"); err != nil {
+			return err
+		}
+	}
+
+	pdfProcessor := pdf.NewPDFProcessor(p.logo)
+	if err := p.setupOutput(pdfProcessor); err != nil {
+		return err
+	}
+	if err := p.setupEncryption(pdfProcessor); err != nil {
+		return err
+	}
+
+	if p.permissions != "" && !p.encrypt && p.pword == "" && p.ownerPword == "" {
+		return utils.NewUsageError("the --permissions flag needs a password, use the --password, --owner-password or --encrypt flags")
+	}
+
+	f, err := p.newFileUtils()
+	if err != nil {
+		return err
 	}
-	f := utils.NewFileUtils(p.args)
 
 	// check if any files/folders are provided
 	pdfs, dir, err := f.CheckProvidedArgs()
@@ -170,7 +425,7 @@ func (p *Program) ExecuteMerge() error {
 
 	if f.Interactive {
 		for {
-			selectedPdfs, quit, err = multiSelect.MultiSelectInteractive(pdfs, dir, p.logo)
+			selectedPdfs, quit, err = multiSelect.MultiSelectInteractive(pdfs, dir, p.logo, f.GetPdfFilesFromDir)
 			if err != nil || quit {
 				return err
 			}
@@ -184,6 +439,11 @@ func (p *Program) ExecuteMerge() error {
 		selectedPdfs = pdfs
 	}
 
+	selectedPdfs, err = p.sortPdfs(dir, selectedPdfs)
+	if err != nil {
+		return err
+	}
+
 	// reordering of the pdfs
 	if p.reorder {
 		selectedPdfs, quit, err = multiReorder.MultiReorderInteractive(selectedPdfs, p.logo)
@@ -194,18 +454,63 @@ func (p *Program) ExecuteMerge() error {
 
 	pdfWithFullPath := f.AddFullPathToPdfs(dir, selectedPdfs)
 
-	pdfProcessor := pdf.NewPDFProcessor(p.logo)
+	merge := func(processor *pdf.PDFProcessor) (string, error) {
+		return processor.MergePdfs(pdfWithFullPath, p.name)
+	}
+
+	// reordering, deleting and rotating single pages
+	if p.pageMode {
+		plan, err := pdf.ExpandPages(pdfWithFullPath)
+		if err != nil {
+			return err
+		}
+
+		plan, quit, err = multiReorder.PageReorderInteractive(plan, p.logo)
+		if err != nil || quit {
+			return err
+		}
 
-	p.name, err = pdfProcessor.MergePdfs(pdfWithFullPath, p.name)
+		merge = func(processor *pdf.PDFProcessor) (string, error) {
+			return processor.MergePages(plan, p.name)
+		}
+	}
+
+	mergedPdf, err := merge(pdfProcessor)
+	overwrite, promptErr := p.confirmOverwrite(err, f.Interactive)
+	if promptErr != nil {
+		return promptErr
+	}
+	if overwrite {
+		mergedPdf, err = merge(pdfProcessor.Force())
+	}
 	if err != nil {
 		return err
 	}
+	p.name = mergedPdf
 
 	saveDir, err := f.GetCurrentWorkingDir()
 	if err != nil {
 		return err
 	}
 
+	// the watermark is stamped before the merged PDF is optimized, so the
+	// optimization covers it as well
+	if p.hasWatermark() {
+		if _, err := pdfProcessor.InPlace().WithoutBackup().WatermarkPdf(p.name, "", "", p.watermark()); err != nil {
+			return err
+		}
+	}
+
+	// the merged PDF is optimized before it's encrypted, an encrypted PDF
+	// can't be optimized without its password
+	if p.optimize {
+		_, note, err := p.optimizePdf(pdfProcessor.InPlace().WithoutBackup(), "", p.name, "")
+		if err != nil {
+			return err
+		}
+		p.cmd.Println(styles.SelectedStyle.Render(fmt.Sprintf("PDF file optimized (%s)", note)))
+	}
+
 	// if the encrypt flag is set, ask for password interactively
 	if p.encrypt {
 		p.pword, quit, err = textInputs.TextinputInteractive()
@@ -217,12 +522,12 @@ func (p *Program) ExecuteMerge() error {
 	}
 
 	// encrypt pdf file if flag is set
-	if p.pword != "" {
-		if err := p.processEncryptPDFs(pdfProcessor, []string{p.name}, saveDir, saveDir, p.pword); err != nil {
+	if p.pword != "" || p.ownerPword != "" {
+		if err := p.processEncryptPDFs(pdfProcessor.InPlace().WithoutBackup(), []string{p.name}, "", saveDir, p.pword, f.Interactive); err != nil {
 			return err
 		}
 	} else {
-		complete := fmt.Sprintf("PDF files merged successfully to: %s/%s", saveDir, p.name)
+		complete := fmt.Sprintf("PDF files merged successfully to: %s", displayPath(saveDir, p.name))
 		p.cmd.Println(styles.InfoStyle.Render(complete))
 	}
 
@@ -230,45 +535,158 @@ func (p *Program) ExecuteMerge() error {
 }
 
 func (p *Program) ExecuteDecrypt() error {
-	var (
-		selectedPdfs []string
-		quit         bool
-		err          error
-	)
+	if err := p.checkBatchFlags(); err != nil {
+		return err
+	}
 
-	f := utils.NewFileUtils(p.args)
+	f, err := p.newFileUtils()
+	if err != nil {
+		return err
+	}
 	pdfProcessor := pdf.NewPDFProcessor(p.logo)
+	if err := p.setupOutput(pdfProcessor); err != nil {
+		return err
+	}
 
-	pdfs, dir, err := f.CheckProvidedArgs()
-	if err != nil {
+	selectedPdfs, dir, saveDir, err := p.selectPdfs(f)
+	if err != nil || len(selectedPdfs) == 0 {
 		return err
 	}
 
-	if f.Interactive {
-		selectedPdfs, quit, err = multiSelect.MultiSelectInteractive(pdfs, dir, p.logo)
-		if err != nil || quit {
+	// with a password map the shared password is only used for the other files
+	if p.pwordMap != "" {
+		if err := p.resolvePassword(); err != nil {
 			return err
 		}
+	} else if err := p.getPassword(); err != nil {
+		return err
+	}
 
-		if len(selectedPdfs) == 0 {
-			p.cmd.Println(styles.InfoStyle.Render("No PDFs were selected. Exiting."))
-			return err
-		}
-	} else {
-		selectedPdfs = pdfs
+	if err := p.processDecryptPDFs(pdfProcessor, selectedPdfs, dir, saveDir, p.pword, f.Interactive); err != nil {
+		return err
 	}
+	return nil
+}
 
-	if err := p.getPassword(); err != nil {
+func (p *Program) checkSplitFlags() error {
+	modes := 0
+	if p.every != 0 {
+		modes++
+	}
+	if p.ranges != "" {
+		modes++
+	}
+	if p.bookmarks {
+		modes++
+	}
+
+	if modes != 1 {
+		return utils.NewUsageError("please provide exactly one of the --every, --ranges or --bookmarks flags")
+	}
+	if p.every < 0 {
+		return utils.NewUsageError("the --every flag must be a positive number of pages")
+	}
+	return nil
+}
+
+func (p *Program) splitPDF(pdfProcessor *pdf.PDFProcessor, file, dir string) ([]string, error) {
+	switch {
+	case p.every > 0:
+		return pdfProcessor.SplitPdfEvery(file, dir, p.name, p.every)
+	case p.ranges != "":
+		return pdfProcessor.SplitPdfByRanges(file, dir, p.name, p.ranges)
+	default:
+		return pdfProcessor.SplitPdfByBookmarks(file, dir, p.name)
+	}
+}
+
+func (p *Program) ExecuteSplit() error {
+	if err := p.checkBatchFlags(); err != nil {
+		return err
+	}
+	if err := p.checkSplitFlags(); err != nil {
 		return err
 	}
 
-	saveDir, err := f.GetCurrentWorkingDir()
+	f, err := p.newFileUtils()
 	if err != nil {
 		return err
 	}
+	pdfProcessor := pdf.NewPDFProcessor(p.logo)
+	if err := p.setupOutput(pdfProcessor); err != nil {
+		return err
+	}
 
-	if err := p.processDecryptPDFs(pdfProcessor, selectedPdfs, dir, saveDir, p.pword); err != nil {
+	selectedPdfs, dir, saveDir, err := p.selectPdfs(f)
+	if err != nil || len(selectedPdfs) == 0 {
+		return err
+	}
+
+	return p.processBatch(pdfProcessor, selectedPdfs, dir, saveDir, f.Interactive, batchStep{
+		verb: "split",
+		done: "split",
+		process: func(pdfProcessor *pdf.PDFProcessor, dir, file string) ([]string, string, error) {
+			splitPdfs, err := p.splitPDF(pdfProcessor, file, dir)
+			return splitPdfs, "", err
+		},
+	})
+}
+
+func (p *Program) getOldAndNewPasswords() error {
+	var (
+		quit bool
+		err  error
+	)
+
+	switch {
+	case p.oldPword != "" && p.newPword != "":
+		return nil
+	case p.oldPword != "":
+		p.newPword, quit, err = textInputs.TextinputInteractive()
+	case p.newPword != "":
+		p.oldPword, quit, err = textInputs.OldPasswordInteractive()
+	default:
+		p.oldPword, p.newPword, quit, err = textInputs.ChangePasswordInteractive()
+	}
+	if err != nil || quit {
 		return err
 	}
 	return nil
 }
+
+func (p *Program) ExecuteChangePassword() error {
+	if err := p.checkBatchFlags(); err != nil {
+		return err
+	}
+
+	f, err := p.newFileUtils()
+	if err != nil {
+		return err
+	}
+	pdfProcessor := pdf.NewPDFProcessor(p.logo)
+	if err := p.setupOutput(pdfProcessor); err != nil {
+		return err
+	}
+
+	selectedPdfs, dir, saveDir, err := p.selectPdfs(f)
+	if err != nil || len(selectedPdfs) == 0 {
+		return err
+	}
+
+	if err := p.getOldAndNewPasswords(); err != nil {
+		return err
+	}
+
+	if p.newPword == "" {
+		return utils.NewUsageError("the new password can't be empty, use the decrypt command to remove the password")
+	}
+
+	return p.processBatch(pdfProcessor, selectedPdfs, dir, saveDir, f.Interactive, batchStep{
+		verb: "change the password of",
+		done: "password changed",
+		process: func(pdfProcessor *pdf.PDFProcessor, dir, file string) ([]string, string, error) {
+			rekeyedPdf, err := pdfProcessor.ChangePassword(file, dir, p.oldPword, p.newPword, p.name)
+			return []string{rekeyedPdf}, "", err
+		},
+	})
+}
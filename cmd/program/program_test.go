@@ -1,7 +1,13 @@
 package program
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	textInputs "github.com/gmskazi/pdfmc/cmd/ui/textinputs"
 	"github.com/spf13/cobra"
@@ -107,6 +113,38 @@ func TestNewProgram(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "encryption flags",
+			args: args{
+				cmd: func() *cobra.Command {
+					cmd := &cobra.Command{}
+					cmd.Flags().String("user-password", "", "")
+					cmd.Flags().String("owner-password", "", "")
+					cmd.Flags().String("permissions", "", "")
+					// Set the values
+					cmd.SetArgs([]string{
+						"--user-password=user",
+						"--owner-password=owner",
+						"--permissions=print,copy",
+					})
+					err := cmd.Execute()
+					assert.NoError(t, err, "error parseing the flags")
+					return cmd
+				}(),
+				args: []string{"file1.pdf"},
+				logo: "encrypt",
+			},
+			want: &Program{
+				cmd:  &cobra.Command{},
+				args: []string{"file1.pdf"},
+				logo: "encrypt",
+				EncryptFlags: EncryptFlags{
+					userPword:   "user",
+					ownerPword:  "owner",
+					permissions: "print,copy",
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -116,6 +154,7 @@ func TestNewProgram(t *testing.T) {
 			assert.Equal(t, tt.want.logo, got.logo, "logo should match")
 			assert.Equal(t, tt.want.MergeFlags, got.MergeFlags, "MergeFlags should match")
 			assert.Equal(t, tt.want.pword, got.pword, "password should match")
+			assert.Equal(t, tt.want.EncryptFlags, got.EncryptFlags, "EncryptFlags should match")
 		})
 	}
 }
@@ -184,8 +223,64 @@ func Test_getPassword(t *testing.T) {
 			expectedError: false,
 			expectedPword: "existingPassword",
 		},
+		{
+			name: "password from environment variable",
+			program: &Program{
+				PasswordFlags: PasswordFlags{pwordEnv: "PDFMC_TEST_PASSWORD"},
+			},
+			inputFunc:     nil,
+			expectedError: false,
+			expectedPword: "envPassword",
+		},
+		{
+			name: "unset environment variable",
+			program: &Program{
+				PasswordFlags: PasswordFlags{pwordEnv: "PDFMC_TEST_UNSET_PASSWORD"},
+			},
+			inputFunc:     nil,
+			expectedError: true,
+		},
+		{
+			name: "password from file",
+			program: &Program{
+				PasswordFlags: PasswordFlags{pwordFile: "password.txt"},
+			},
+			inputFunc:     nil,
+			expectedError: false,
+			expectedPword: "filePassword",
+		},
+		{
+			name: "password from stdin",
+			program: &Program{
+				cmd: func() *cobra.Command {
+					cmd := &cobra.Command{}
+					cmd.SetIn(strings.NewReader("stdinPassword\n"))
+					return cmd
+				}(),
+				PasswordFlags: PasswordFlags{pwordStdin: true},
+			},
+			inputFunc:     nil,
+			expectedError: false,
+			expectedPword: "stdinPassword",
+		},
+		{
+			name: "more than one password source",
+			program: &Program{
+				pword:         "existingPassword",
+				PasswordFlags: PasswordFlags{pwordEnv: "PDFMC_TEST_PASSWORD"},
+			},
+			inputFunc:     nil,
+			expectedError: true,
+		},
 	}
 
+	t.Setenv("PDFMC_TEST_PASSWORD", "envPassword")
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+	err = os.WriteFile(filepath.Join(tempDir, "password.txt"), []byte("filePassword\r\n"), 0600)
+	assert.NoError(t, err, "failed to create the password file")
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.inputFunc != nil {
@@ -205,3 +300,203 @@ func Test_getPassword(t *testing.T) {
 		})
 	}
 }
+
+func Test_loadPasswordMap(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		content     string
+		expected    map[string]string
+		expectedErr bool
+	}{
+		{
+			name:    "csv password map",
+			file:    "passwords.csv",
+			content: "# vendor passwords\nvendor-a.pdf,secretA\nreports/*.pdf, secretB\n",
+			expected: map[string]string{
+				"vendor-a.pdf":      "secretA",
+				"reports/q1.pdf":    "secretB",
+				"other.pdf":         "",
+				"reports/q2.pdf":    "secretB",
+				"/tmp/vendor-a.pdf": "secretA",
+			},
+		},
+		{
+			name:    "json password map",
+			file:    "passwords.json",
+			content: `{"*.pdf": "fallback", "vendor-a.pdf": "secretA"}`,
+			expected: map[string]string{
+				"vendor-a.pdf": "secretA",
+				"other.pdf":    "fallback",
+			},
+		},
+		{
+			name:        "invalid csv password map",
+			file:        "passwords.csv",
+			content:     "vendor-a.pdf\n",
+			expectedErr: true,
+		},
+		{
+			name:        "invalid json password map",
+			file:        "passwords.json",
+			content:     `["vendor-a.pdf"]`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			err := os.WriteFile(path, []byte(tt.content), 0600)
+			assert.NoError(t, err, "failed to create the password map")
+
+			mappings, err := loadPasswordMap(path)
+			if tt.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			for file, expected := range tt.expected {
+				pword, ok := passwordFor(mappings, file)
+				assert.Equal(t, expected != "", ok, "unexpected match for %s", file)
+				assert.Equal(t, expected, pword, "unexpected password for %s", file)
+			}
+		})
+	}
+}
+
+func Test_runBatch(t *testing.T) {
+	files := []string{"file1.pdf", "file2.pdf", "file3.pdf", "file4.pdf", "file5.pdf", "file6.pdf"}
+
+	tests := []struct {
+		name          string
+		jobs          int
+		stopAt        string
+		expectedFiles []string
+		expectedError bool
+	}{
+		{
+			name:          "one worker",
+			jobs:          1,
+			expectedFiles: files,
+		},
+		{
+			name:          "more workers than files",
+			jobs:          10,
+			expectedFiles: files,
+		},
+		{
+			name:          "stop the batch",
+			jobs:          3,
+			stopAt:        "file3.pdf",
+			expectedFiles: files[:3],
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Program{BatchFlags: BatchFlags{jobs: tt.jobs}}
+
+			var reported []string
+			results, err := p.runBatch(files, func(index int, file string) fileResult {
+				// finish the files in reverse order to check the reporting order
+				time.Sleep(time.Duration(len(files)-slices.Index(files, file)) * time.Millisecond)
+				return fileResult{file: file}
+			}, func(result *fileResult) error {
+				reported = append(reported, result.file)
+				if result.file == tt.stopAt {
+					return errors.New("stop")
+				}
+				return nil
+			})
+
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectedFiles, reported, "files should be reported in order")
+			assert.Len(t, results, len(tt.expectedFiles))
+		})
+	}
+}
+
+func Test_sortPdfs(t *testing.T) {
+	tempDir := t.TempDir()
+	files := []string{"page10.pdf", "page2.pdf", "page1.pdf"}
+	sizes := []int{30, 10, 20}
+	ages := []time.Duration{time.Hour, 3 * time.Hour, 2 * time.Hour}
+	for i, file := range files {
+		path := filepath.Join(tempDir, file)
+		err := os.WriteFile(path, []byte(strings.Repeat("x", sizes[i])), 0644)
+		assert.NoError(t, err)
+		modTime := time.Now().Add(-ages[i])
+		err = os.Chtimes(path, modTime, modTime)
+		assert.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		sortBy   string
+		reverse  bool
+		pdfs     []string
+		expected []string
+	}{
+		{
+			name:     "no sort order",
+			pdfs:     files,
+			expected: files,
+		},
+		{
+			name:     "name",
+			sortBy:   "name",
+			pdfs:     files,
+			expected: []string{"page1.pdf", "page10.pdf", "page2.pdf"},
+		},
+		{
+			name:     "natural",
+			sortBy:   "natural",
+			pdfs:     files,
+			expected: []string{"page1.pdf", "page2.pdf", "page10.pdf"},
+		},
+		{
+			name:     "natural reversed",
+			sortBy:   "natural",
+			reverse:  true,
+			pdfs:     files,
+			expected: []string{"page10.pdf", "page2.pdf", "page1.pdf"},
+		},
+		{
+			name:     "modification time",
+			sortBy:   "mtime",
+			pdfs:     files,
+			expected: []string{"page2.pdf", "page1.pdf", "page10.pdf"},
+		},
+		{
+			name:     "size with page selections",
+			sortBy:   "size",
+			pdfs:     []string{"page10.pdf[1]", "page2.pdf", "page1.pdf[1-2]"},
+			expected: []string{"page2.pdf", "page1.pdf[1-2]", "page10.pdf[1]"},
+		},
+		{
+			name:     "only reversed",
+			reverse:  true,
+			pdfs:     files,
+			expected: []string{"page1.pdf", "page2.pdf", "page10.pdf"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Program{MergeFlags: MergeFlags{sortBy: tt.sortBy, reverse: tt.reverse}}
+			sorted, err := p.sortPdfs(tempDir, tt.pdfs)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, sorted)
+		})
+	}
+
+	assert.NoError(t, checkSortOrder("natural"))
+	assert.ErrorContains(t, checkSortOrder("pages"), "unknown sort order pages")
+}
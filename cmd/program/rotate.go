@@ -0,0 +1,56 @@
+package program
+
+import (
+	"math"
+
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+type RotateFlags struct {
+	degrees      int
+	rotatePages  string
+	autoPortrait bool
+}
+
+func (p *Program) checkRotateFlags() error {
+	if p.autoPortrait && p.degrees == 0 {
+		p.degrees = 90
+	}
+
+	switch {
+	case p.degrees == 0:
+		return utils.NewUsageError("please provide the --degrees or --auto-portrait flag")
+	case p.degrees != 90 && p.degrees != 180 && p.degrees != 270:
+		return utils.NewUsageError("the --degrees flag must be 90, 180 or 270")
+	case p.autoPortrait && p.degrees == 180:
+		return utils.NewUsageError("the --auto-portrait flag rotates by 90 or 270 degrees")
+	}
+
+	// the ranges are checked against every PDF, only the syntax is checked here
+	if p.rotatePages != "" {
+		if _, err := pdf.ParsePageRanges(p.rotatePages, math.MaxInt32); err != nil {
+			return &utils.UsageError{Err: err}
+		}
+	}
+	return nil
+}
+
+func (p *Program) rotation() pdf.Rotation {
+	return pdf.Rotation{Degrees: p.degrees, Pages: p.rotatePages, AutoPortrait: p.autoPortrait}
+}
+
+func (p *Program) ExecuteRotate() error {
+	if err := p.checkRotateFlags(); err != nil {
+		return err
+	}
+
+	return p.executeBatch(batchStep{
+		verb: "rotate",
+		done: "rotated",
+		process: func(pdfProcessor *pdf.PDFProcessor, dir, file string) ([]string, string, error) {
+			rotatedPdf, err := pdfProcessor.RotatePdf(file, dir, p.name, p.rotation())
+			return []string{rotatedPdf}, "", err
+		},
+	})
+}
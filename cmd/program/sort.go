@@ -0,0 +1,95 @@
+package program
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+var SortOrders = []string{"name", "natural", "mtime", "ctime", "size", "title"}
+
+func checkSortOrder(sortBy string) error {
+	if sortBy != "" && !slices.Contains(SortOrders, sortBy) {
+		return utils.NewUsageError("unknown sort order %s, valid orders are: %s", sortBy, strings.Join(SortOrders, ", "))
+	}
+	return nil
+}
+
+type sortKey struct {
+	pdf   string
+	time  time.Time
+	size  int64
+	title string
+}
+
+func (p *Program) sortPdfs(dir string, pdfs []string) ([]string, error) {
+	if p.sortBy == "" && !p.reverse {
+		return pdfs, nil
+	}
+
+	keys := make([]sortKey, len(pdfs))
+	for i, file := range pdfs {
+		keys[i].pdf = file
+		if err := p.readSortKey(dir, &keys[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		switch p.sortBy {
+		case "name":
+			return a.pdf < b.pdf
+		case "natural":
+			return utils.NaturalLess(a.pdf, b.pdf)
+		case "mtime", "ctime":
+			return a.time.Before(b.time)
+		case "size":
+			return a.size < b.size
+		case "title":
+			return utils.NaturalLess(a.title, b.title)
+		}
+		return false
+	})
+
+	sorted := make([]string, len(keys))
+	for i, key := range keys {
+		sorted[i] = key.pdf
+	}
+	if p.reverse {
+		slices.Reverse(sorted)
+	}
+	return sorted, nil
+}
+
+func (p *Program) readSortKey(dir string, key *sortKey) error {
+	file, _ := utils.SplitPageSelection(key.pdf)
+	path := filepath.Join(dir, file)
+
+	switch p.sortBy {
+	case "mtime", "ctime", "size":
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		key.size = info.Size()
+		key.time = info.ModTime()
+		if p.sortBy == "ctime" {
+			key.time = utils.ChangeTime(info)
+		}
+	case "title":
+		title, err := pdf.Title(path)
+		if err != nil {
+			return fmt.Errorf("failed to read the title of %s: %w", file, err)
+		}
+		key.title = title
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package program
+
+import (
+	"cmp"
+	"os"
+
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+	"github.com/spf13/cobra"
+)
+
+type WatermarkFlags struct {
+	watermarkText  string
+	watermarkImage string
+	font           string
+	fontSize       int
+	color          string
+	opacity        float64
+	angle          float64
+	position       string
+	scale          float64
+	underlay       bool
+	watermarkPages string
+}
+
+// newWatermarkFlags reads --text and --image, or --watermark and
+// --watermark-image on merge and encrypt.
+func newWatermarkFlags(cmd *cobra.Command) WatermarkFlags {
+	defaults := pdf.DefaultWatermark()
+
+	return WatermarkFlags{
+		watermarkText:  cmp.Or(getFlagValue(cmd.Flag("text")), getFlagValue(cmd.Flag("watermark"))),
+		watermarkImage: cmp.Or(getFlagValue(cmd.Flag("image")), getFlagValue(cmd.Flag("watermark-image"))),
+		font:           cmp.Or(getFlagValue(cmd.Flag("font")), defaults.Font),
+		fontSize:       getFlagIntValueOr(cmd, "size", defaults.Size),
+		color:          cmp.Or(getFlagValue(cmd.Flag("color")), defaults.Color),
+		opacity:        getFlagFloat64ValueOr(cmd, "opacity", defaults.Opacity),
+		angle:          getFlagFloat64ValueOr(cmd, "rotation", defaults.Rotation),
+		position:       cmp.Or(getFlagValue(cmd.Flag("position")), defaults.Position),
+		scale:          getFlagFloat64ValueOr(cmd, "scale", defaults.Scale),
+		underlay:       getFlagBoolValue(cmd, "underlay"),
+		watermarkPages: getFlagValue(cmd.Flag("pages")),
+	}
+}
+
+func getFlagIntValueOr(cmd *cobra.Command, flagname string, fallback int) int {
+	value, err := cmd.Flags().GetInt(flagname)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getFlagFloat64ValueOr(cmd *cobra.Command, flagname string, fallback float64) float64 {
+	value, err := cmd.Flags().GetFloat64(flagname)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func (p *Program) hasWatermark() bool {
+	return p.watermarkText != "" || p.watermarkImage != ""
+}
+
+func (p *Program) watermark() pdf.Watermark {
+	return pdf.Watermark{
+		Text:     p.watermarkText,
+		Image:    p.watermarkImage,
+		Font:     p.font,
+		Size:     p.fontSize,
+		Color:    p.color,
+		Opacity:  p.opacity,
+		Rotation: p.angle,
+		Position: p.position,
+		Scale:    p.scale,
+		Underlay: p.underlay,
+		Pages:    p.watermarkPages,
+	}
+}
+
+func (p *Program) checkWatermarkFlags(text, image string) error {
+	if p.watermarkText != "" && p.watermarkImage != "" {
+		return utils.NewUsageError("please provide either the --%s flag or the --%s flag", text, image)
+	}
+	if err := p.watermark().Check(); err != nil {
+		return &utils.UsageError{Err: err}
+	}
+	if p.watermarkImage != "" {
+		if _, err := os.Stat(p.watermarkImage); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Program) setupWatermark(pdfProcessor *pdf.PDFProcessor) error {
+	if !p.hasWatermark() {
+		return nil
+	}
+	if err := p.checkWatermarkFlags("watermark", "watermark-image"); err != nil {
+		return err
+	}
+	pdfProcessor.SetWatermark(p.watermark())
+	return nil
+}
+
+func (p *Program) ExecuteWatermark() error {
+	if !p.hasWatermark() {
+		return utils.NewUsageError("please provide the --text or --image flag")
+	}
+	if err := p.checkWatermarkFlags("text", "image"); err != nil {
+		return err
+	}
+
+	return p.executeBatch(batchStep{
+		verb: "watermark",
+		done: "watermarked",
+		process: func(pdfProcessor *pdf.PDFProcessor, dir, file string) ([]string, string, error) {
+			watermarkedPdf, err := pdfProcessor.WatermarkPdf(file, dir, p.name, p.watermark())
+			return []string{watermarkedPdf}, "", err
+		},
+	})
+}
@@ -6,6 +6,8 @@ package cmd
 import (
 	"os"
 
+	"github.com/gmskazi/pdfmc/cmd/styles"
+	"github.com/gmskazi/pdfmc/cmd/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +16,9 @@ var rootCmd = &cobra.Command{
 	Use:   "pdfmc",
 	Short: "A PDF Merge and Encrypt tool.",
 	Long:  `This is a tool to merge and encrypt PDFs.`,
+	// errors are printed by Execute so they can be styled, the commands
+	// silence the usage once their flags have been parsed
+	SilenceErrors: true,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	// Run: func(cmd *cobra.Command, args []string) { },
@@ -22,13 +27,35 @@ var rootCmd = &cobra.Command{
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	cmd, err := executeC()
 	if err != nil {
-		os.Exit(1)
+		cmd.PrintErrln(styles.ErrorStyle.Render(err.Error()))
+		os.Exit(exitCodeFor(err))
+	}
+}
+
+func executeC() (*cobra.Command, error) {
+	cmd, err := rootCmd.ExecuteC()
+	if err != nil && !cmd.Runnable() {
+		err = &utils.UsageError{Err: err}
+	}
+	return cmd, err
+}
+
+func usageArgs(check cobra.PositionalArgs) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if err := check(cmd, args); err != nil {
+			return &utils.UsageError{Err: err}
+		}
+		return nil
 	}
 }
 
 func init() {
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &utils.UsageError{Err: err}
+	})
+
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 Aito Nakajima
+*/
+package cmd
+
+import (
+	"runtime"
+
+	"github.com/gmskazi/pdfmc/cmd/autocomplete"
+	"github.com/gmskazi/pdfmc/cmd/program"
+	"github.com/spf13/cobra"
+)
+
+var rotateCmd = &cobra.Command{
+	Use:   "rotate [files... or folder]",
+	Short: "Rotate the pages of PDF files.",
+	Long: `This is a tool to rotate the pages of PDF files, all of them, a range of
+pages or only the pages that are shown in landscape.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		p := program.NewProgram(cmd, args, rotate)
+		return p.ExecuteRotate()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rotateCmd)
+
+	rotateCmd.Flags().IntP("degrees", "d", 0, "Rotate the pages clockwise by 90, 180 or 270 degrees.")
+	rotateCmd.Flags().String("pages", "", "Only rotate these pages, e.g. 1-3,5 or 11-")
+	rotateCmd.Flags().Bool("auto-portrait", false, "Only rotate the landscape pages to portrait, by 90 degrees unless --degrees is 270.")
+	rotateCmd.Flags().StringP("name", "n", "", "Add a prefix to the beginning of the file name.")
+	rotateCmd.Flags().Bool("recursive", false, "Search the directories for PDF files recursively.")
+	rotateCmd.Flags().StringSlice("include", nil, "Only use the PDF files in the directories that match one of these globs.")
+	rotateCmd.Flags().StringSlice("exclude", nil, "Skip the PDF files in the directories that match one of these globs.")
+	rotateCmd.Flags().String("output-dir", "", "Directory to write the PDF files to, it's created when it doesn't exist.")
+	rotateCmd.Flags().String("name-template", "", nameTemplateUsage)
+	rotateCmd.Flags().BoolP("force", "f", false, "Overwrite files that already exist.")
+	rotateCmd.Flags().Bool("backup", false, "Keep a copy of every file that is overwritten, restore it with the undo command.")
+	rotateCmd.Flags().Bool("in-place", false, "Overwrite the original PDF files when there is no prefix or output directory.")
+	rotateCmd.Flags().BoolP("keep-going", "k", false, "Keep going when a file fails and print a summary at the end.")
+	rotateCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of PDF files to process at the same time.")
+
+	rotateCmd.ValidArgsFunction = autocomplete.GetSuggestions
+}
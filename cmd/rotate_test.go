@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/stretchr/testify/assert"
+)
+
+// Only testing non interactive mode for now
+func TestRotateCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		pdfs           []string
+		flags          []string
+		fileOutputs    []string
+		expectError    bool
+		exitCode       int
+		expectedOutput string
+		encrypted      bool
+		merge          bool
+	}{
+		{
+			name:           "Rotate a PDF file in place",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{rotate, "file1.pdf", "-d", "90", "--in-place"},
+			fileOutputs:    []string{"file1.pdf"},
+			expectedOutput: "PDF file rotated successfully to:",
+		},
+		{
+			name:           "Rotate a page range with a prefix",
+			pdfs:           []string{"file1.pdf", "file2.pdf"},
+			flags:          []string{rotate, "merged_output.pdf", "-d", "180", "--pages", "2", "-n", "rotated-"},
+			fileOutputs:    []string{"rotated-merged_output.pdf"},
+			expectedOutput: "PDF file rotated successfully to:",
+			merge:          true,
+		},
+		{
+			name:           "Rotate the landscape pages of several files",
+			pdfs:           []string{"file1.pdf", "file2.pdf"},
+			flags:          []string{rotate, "file1.pdf", "file2.pdf", "--auto-portrait", "--output-dir", "portrait"},
+			fileOutputs:    []string{"portrait/file1.pdf", "portrait/file2.pdf"},
+			expectedOutput: "PDF file rotated successfully to:",
+		},
+		{
+			name:           "No rotation provided",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{rotate, "file1.pdf", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "please provide the --degrees or --auto-portrait flag",
+		},
+		{
+			name:           "Invalid degrees",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{rotate, "file1.pdf", "-d", "45", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "the --degrees flag must be 90, 180 or 270",
+		},
+		{
+			name:           "Auto portrait upside down",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{rotate, "file1.pdf", "--auto-portrait", "-d", "180", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "the --auto-portrait flag rotates by 90 or 270 degrees",
+		},
+		{
+			name:           "Malformed page range",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{rotate, "file1.pdf", "-d", "90", "--pages", "1-a", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "invalid page range",
+		},
+		{
+			name:           "Page range out of bounds",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{rotate, "file1.pdf", "-d", "90", "--pages", "2", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "out of bounds",
+		},
+		{
+			name:           "Original is not overwritten without in place",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{rotate, "file1.pdf", "-d", "90"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "use --in-place to overwrite the original PDF files",
+		},
+		{
+			name:           "Encrypted PDF file",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{rotate, "file1.pdf", "-d", "90", "--in-place"},
+			expectError:    true,
+			exitCode:       exitInvalidPDF,
+			expectedOutput: "file1.pdf is encrypted, decrypt it first",
+			encrypted:      true,
+		},
+		{
+			name:           "Check if files are available",
+			pdfs:           nil,
+			flags:          []string{rotate, "file1.pdf", "-d", "90"},
+			expectError:    true,
+			exitCode:       exitIO,
+			expectedOutput: "no such file or directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t, rotateCmd)
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+			createTestFiles(t, tempDir, tt.pdfs)
+			if tt.encrypted {
+				encryptTestFiles(t, tempDir, tt.pdfs, "test", "")
+			}
+			if tt.merge {
+				_, err := pdf.NewPDFProcessor(merge).MergePdfs(tt.pdfs, "merged_output")
+				assert.NoError(t, err, "failed to merge test files")
+			}
+
+			var outputBuf bytes.Buffer
+
+			rootCmd.SetOut(&outputBuf)
+			rootCmd.SetErr(&outputBuf)
+			rootCmd.SetArgs(tt.flags)
+
+			err = rootCmd.Execute()
+
+			if tt.expectError {
+				assert.ErrorContains(t, err, tt.expectedOutput, "Expected an error but command ran successfully.")
+			} else {
+				assert.NoError(t, err, "Expected command to run successfully but it failed.")
+				assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Expected output to contain: %s", tt.expectedOutput)
+			}
+			assert.Equal(t, tt.exitCode, exitCodeFor(err), "Unexpected exit code.")
+
+			for _, f := range tt.fileOutputs {
+				_, err := os.Stat(f)
+				assert.NoError(t, err, "Expected file %s to be created but it was not found.", f)
+			}
+		})
+	}
+}
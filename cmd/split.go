@@ -0,0 +1,47 @@
+/*
+Copyright © 2025 Aito Nakajima
+*/
+package cmd
+
+import (
+	"runtime"
+
+	"github.com/gmskazi/pdfmc/cmd/autocomplete"
+	"github.com/gmskazi/pdfmc/cmd/program"
+	"github.com/spf13/cobra"
+)
+
+// splitCmd represents the split command
+var splitCmd = &cobra.Command{
+	Use:   "split [files... or folder]",
+	Short: "Split PDF files apart.",
+	Long: `This is a tool to split PDF files apart by a fixed number of pages,
+explicit page ranges or along the top level bookmarks.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		p := program.NewProgram(cmd, args, split)
+		return p.ExecuteSplit()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(splitCmd)
+
+	splitCmd.Flags().IntP("every", "e", 0, "Split the PDF files into chunks of this many pages.")
+	splitCmd.Flags().StringP("ranges", "r", "", "Split the PDF files by page ranges, e.g. 1-3,4-10,11-")
+	splitCmd.Flags().BoolP("bookmarks", "b", false, "Split the PDF files into one file per top level bookmark.")
+	splitCmd.Flags().StringP("name", "n", "", "Add a prefix to the beginning of the file names.")
+	splitCmd.Flags().Bool("recursive", false, "Search the directories for PDF files recursively.")
+	splitCmd.Flags().StringSlice("include", nil, "Only use the PDF files in the directories that match one of these globs.")
+	splitCmd.Flags().StringSlice("exclude", nil, "Skip the PDF files in the directories that match one of these globs.")
+	splitCmd.Flags().String("output-dir", "", "Directory to write the PDF files to, it's created when it doesn't exist.")
+	splitCmd.Flags().String("name-template", "", nameTemplateUsage)
+	splitCmd.Flags().BoolP("force", "f", false, "Overwrite files that already exist.")
+	splitCmd.Flags().Bool("backup", false, "Keep a copy of every file that is overwritten, restore it with the undo command.")
+	splitCmd.Flags().BoolP("keep-going", "k", false, "Keep going when a file fails and print a summary at the end.")
+	splitCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of PDF files to process at the same time.")
+
+	// autocomplete for files
+	splitCmd.ValidArgsFunction = autocomplete.GetSuggestions
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+// resetFlags restores the flags of a command to their defaults, the commands
+// are package globals so flag values would otherwise leak between test cases.
+func resetFlags(t *testing.T, cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		var err error
+		// setting the "[]" default of a slice flag would append to it
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			err = slice.Replace(nil)
+		} else {
+			err = f.Value.Set(f.DefValue)
+		}
+		assert.NoError(t, err, "failed to reset flag: ", f.Name)
+		f.Changed = false
+	})
+}
+
+// Only testing non interactive mode for now
+func TestSplitCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		pdfs           []string
+		flags          []string
+		fileOutputs    []string
+		expectError    bool
+		exitCode       int
+		expectedOutput string
+		merge          bool
+	}{
+		{
+			name:           "Split a PDF file every page",
+			pdfs:           []string{"file1.pdf", "file2.pdf"},
+			flags:          []string{split, "merged_output.pdf", "--every", "1"},
+			fileOutputs:    []string{"merged_output_1.pdf", "merged_output_2.pdf"},
+			expectError:    false,
+			expectedOutput: "PDF file split successfully to:",
+			merge:          true,
+		},
+		{
+			name:           "Split a PDF file by ranges with a prefix",
+			pdfs:           []string{"file1.pdf", "file2.pdf"},
+			flags:          []string{split, "merged_output.pdf", "-r", "1-2", "-n", "test-"},
+			fileOutputs:    []string{"test-merged_output_1-2.pdf"},
+			expectError:    false,
+			expectedOutput: "PDF file split successfully to:",
+			merge:          true,
+		},
+		{
+			name:           "Split a PDF file by bookmarks",
+			pdfs:           []string{"file1.pdf", "file2.pdf"},
+			flags:          []string{split, "merged_output.pdf", "--bookmarks"},
+			expectError:    false,
+			expectedOutput: "PDF file split successfully to:",
+			merge:          true,
+		},
+		{
+			name:           "Split a PDF file into an output directory with a name template",
+			pdfs:           []string{"file1.pdf", "file2.pdf"},
+			flags:          []string{split, "merged_output.pdf", "-e", "1", "--output-dir", "parts", "--name-template", "{stem}-p{pages}"},
+			fileOutputs:    []string{"parts/merged_output-p1.pdf", "parts/merged_output-p2.pdf"},
+			expectError:    false,
+			expectedOutput: "PDF file split successfully to:",
+			merge:          true,
+		},
+		{
+			name:           "Name template without pages",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{split, "file1.pdf", "-e", "1", "--name-template", "{stem}-{index}"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "the --name-template flag needs the {pages} token to split PDF files",
+		},
+		{
+			name:           "Range out of bounds",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{split, "file1.pdf", "-r", "1-3"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "out of bounds",
+		},
+		{
+			name:           "No split mode provided",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{split, "file1.pdf"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "please provide exactly one of the --every, --ranges or --bookmarks flags",
+		},
+		{
+			name:           "Check if files are available",
+			pdfs:           nil,
+			flags:          []string{split, "file1.pdf", "-e", "1"},
+			expectError:    true,
+			exitCode:       exitIO,
+			expectedOutput: "no such file or directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t, splitCmd)
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+			createTestFiles(t, tempDir, tt.pdfs)
+			if tt.merge {
+				_, err := pdf.NewPDFProcessor(merge).MergePdfs(tt.pdfs, "merged_output")
+				assert.NoError(t, err, "failed to merge test files")
+			}
+
+			var outputBuf bytes.Buffer
+
+			rootCmd.SetOut(&outputBuf)
+			rootCmd.SetErr(&outputBuf)
+			rootCmd.SetArgs(tt.flags)
+
+			err = rootCmd.Execute()
+
+			if tt.expectError {
+				assert.ErrorContains(t, err, tt.expectedOutput, "Expected an error but command ran successfully.")
+			} else {
+				assert.NoError(t, err, "Expected command to run successfully but it failed.")
+				assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Expected output to contain: %s", tt.expectedOutput)
+			}
+			assert.Equal(t, tt.exitCode, exitCodeFor(err), "Unexpected exit code.")
+
+			for _, f := range tt.fileOutputs {
+				_, err := os.Stat(f)
+				assert.NoError(t, err, "Expected file %s to be created but it was not found.", f)
+			}
+		})
+	}
+}
+
+func TestSplitCommandKeepGoing(t *testing.T) {
+	resetFlags(t, splitCmd)
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+	createTestFiles(t, tempDir, []string{"file1.pdf", "file2.pdf", "file3.pdf"})
+	encryptTestFiles(t, tempDir, []string{"file2.pdf"}, "test", "")
+
+	var outputBuf bytes.Buffer
+
+	rootCmd.SetOut(&outputBuf)
+	rootCmd.SetErr(&outputBuf)
+	rootCmd.SetArgs([]string{split, "file1.pdf", "file2.pdf", "file3.pdf", "-e", "1", "-k"})
+
+	err = rootCmd.Execute()
+	assert.EqualError(t, err, "failed to split 1 of 3 PDF files")
+	assert.Equal(t, exitWrongPassword, exitCodeFor(err), "Expected the exit code of a wrong password")
+
+	output := outputBuf.String()
+	assert.Contains(t, output, "PDF file split successfully to: "+tempDir+"/file1_1.pdf")
+	assert.Contains(t, output, "PDF file split successfully to: "+tempDir+"/file3_1.pdf")
+	assert.Contains(t, output, "2 succeeded, 1 failed")
+}
@@ -0,0 +1,93 @@
+package confirm
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+type Answer int
+
+const (
+	No Answer = iota
+	Yes
+	All
+)
+
+var (
+	defaultStyle = lipgloss.NewStyle().PaddingLeft(1).Foreground(lipgloss.Color("#5dd2fc")).Bold(true)
+	focusedStyle = lipgloss.NewStyle().PaddingLeft(1).Foreground(lipgloss.Color("#FCBD5F")).Bold(true)
+)
+
+type Tmodel struct {
+	question string
+	answer   Answer
+	Quit     bool
+}
+
+func ConfirmModel(question string) Tmodel {
+	return Tmodel{
+		question: question,
+	}
+}
+
+func (m Tmodel) Init() tea.Cmd {
+	return nil
+}
+
+func (m Tmodel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch strings.ToLower(msg.String()) {
+		case "ctrl+c", "esc":
+			m.Quit = true
+			return m, tea.Quit
+
+		case "y":
+			m.answer = Yes
+			return m, tea.Quit
+
+		case "a":
+			m.answer = All
+			return m, tea.Quit
+
+		case "n", "enter":
+			m.answer = No
+			return m, tea.Quit
+		}
+	}
+	return m, nil
+}
+
+func (m Tmodel) View() string {
+	var b strings.Builder
+
+	b.WriteString(defaultStyle.Render(m.question))
+	fmt.Fprint(&b, "\n")
+	b.WriteString(focusedStyle.Render("Press y for yes, a for yes to all, n or enter for no, esc to quit."))
+	fmt.Fprint(&b, "\n")
+
+	return b.String()
+}
+
+func (m Tmodel) GetAnswer() Answer {
+	return m.answer
+}
+
+func ConfirmInteractive(question string) (answer Answer, quit bool, err error) {
+	p := tea.NewProgram(ConfirmModel(question))
+	result, err := p.Run()
+	if err != nil {
+		return No, false, err
+	}
+
+	model := result.(Tmodel)
+	if model.Quit {
+		return No, true, utils.ErrCanceled
+	}
+
+	return model.GetAnswer(), false, nil
+}
@@ -6,6 +6,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gmskazi/pdfmc/cmd/utils"
 )
 
 const logoMerge = `
@@ -117,7 +118,7 @@ func MultiReorderInteractive(pdfs []string, logo string) (reorderedPdfs []string
 
 	model := result.(Tmodel)
 	if model.Quit {
-		return nil, true, fmt.Errorf("operation canceled")
+		return nil, true, utils.ErrCanceled
 	}
 
 	selectedPdfs := model.GetOrderedPdfs()
@@ -0,0 +1,243 @@
+package multiReorder
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/ui/preview"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+type PagesModel struct {
+	pages       []pdf.PlannedPage
+	cursor      int
+	offset      int
+	height      int
+	preview     preview.Pane
+	showPreview bool
+	logo        string
+	Quit        bool
+}
+
+func PageReorderModel(pages []pdf.PlannedPage, logo string) PagesModel {
+	return PagesModel{
+		pages:   slices.Clone(pages),
+		preview: preview.New(preview.Detect()),
+		logo:    logo,
+	}
+}
+
+func (m PagesModel) Init() tea.Cmd {
+	return tea.ClearScreen
+}
+
+func (m PagesModel) GetPagePlan() []pdf.PlannedPage {
+	return m.pages
+}
+
+func (m PagesModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	m = model.(PagesModel)
+	if !m.showPreview || m.Quit || len(m.pages) == 0 {
+		return m, cmd
+	}
+	return m, tea.Batch(cmd, m.preview.Load(m.previewKey()))
+}
+
+func (m PagesModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case preview.Msg:
+		m.preview = m.preview.Update(msg)
+		return m, nil
+
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.preview = m.preview.Resize(msg.Width, msg.Height)
+		return m.move(0), nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			m.Quit = true
+			return m, tea.Quit
+
+		case "k":
+			return m.move(-1), nil
+
+		case "j":
+			return m.move(1), nil
+
+		case "up":
+			if m.cursor > 0 {
+				m.pages[m.cursor], m.pages[m.cursor-1] = m.pages[m.cursor-1], m.pages[m.cursor]
+				return m.move(-1), nil
+			}
+
+		case "down":
+			if m.cursor < len(m.pages)-1 {
+				m.pages[m.cursor], m.pages[m.cursor+1] = m.pages[m.cursor+1], m.pages[m.cursor]
+				return m.move(1), nil
+			}
+
+		case "d", "delete":
+			if len(m.pages) > 0 {
+				m.pages = slices.Delete(m.pages, m.cursor, m.cursor+1)
+				return m.move(0), nil
+			}
+
+		case "c":
+			if len(m.pages) > 0 {
+				m.pages = slices.Insert(m.pages, m.cursor+1, m.pages[m.cursor])
+				return m.move(1), nil
+			}
+
+		case "r":
+			m = m.rotate(90)
+
+		case "R":
+			m = m.rotate(-90)
+
+		case "v":
+			m.showPreview = !m.showPreview
+			m = m.move(0)
+			if !m.showPreview {
+				// images stay on the screen until it's cleared
+				return m, tea.ClearScreen
+			}
+
+		case "enter":
+			if len(m.pages) > 0 {
+				return m, tea.Quit
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m PagesModel) rotate(degrees int) PagesModel {
+	if len(m.pages) == 0 {
+		return m
+	}
+	m.pages[m.cursor].Rotation = (m.pages[m.cursor].Rotation + degrees + 360) % 360
+	return m
+}
+
+func (m PagesModel) move(delta int) PagesModel {
+	m.cursor = max(min(m.cursor+delta, len(m.pages)-1), 0)
+
+	rows := m.rows()
+	if rows == 0 {
+		return m
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+	m.offset = max(min(m.offset, len(m.pages)-rows), 0)
+	return m
+}
+
+func (m PagesModel) rows() int {
+	if m.height == 0 {
+		return 0
+	}
+	height := m.height - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView())
+	if m.showPreview {
+		height -= lipgloss.Height(m.previewView())
+	}
+	return max(height, 1)
+}
+
+func (m PagesModel) previewKey() preview.Key {
+	if len(m.pages) == 0 {
+		return preview.Key{}
+	}
+	page := m.pages[m.cursor]
+	return preview.Key{File: page.File, Page: page.Page, Rotation: page.Rotation}
+}
+
+func (m PagesModel) previewView() string {
+	return m.preview.View(m.previewKey())
+}
+
+func (m PagesModel) headerView() string {
+	var b strings.Builder
+	b.WriteString((defaultStyle.Render(logoMerge)))
+
+	fmt.Fprint(&b, "\n\n")
+	b.WriteString(focusedStyle.Render("Reorder the pages:"))
+	fmt.Fprint(&b, "\n")
+	b.WriteString(focusedStyle.Render("Navigate using the 'j/k' keys, reorder using 'up/down' keys."))
+	fmt.Fprint(&b, "\n")
+	b.WriteString(focusedStyle.Render("Delete a page with 'd', duplicate it with 'c' and rotate it with 'r/R'."))
+	fmt.Fprint(&b, "\n")
+	b.WriteString(focusedStyle.Render("Press 'v' to show a preview of the page."))
+	fmt.Fprint(&b, "\n\n")
+	return b.String()
+}
+
+func (m PagesModel) footerView() string {
+	if len(m.pages) == 0 {
+		return focusedStyle.Render("\nAll pages were deleted, press esc to quit.") + "\n\n"
+	}
+	return focusedStyle.Render(fmt.Sprintf("\n%d pages, press enter to confirm, esc to quit.", len(m.pages))) + "\n\n"
+}
+
+func (m PagesModel) View() string {
+	var b strings.Builder
+	b.WriteString(m.headerView())
+
+	end := len(m.pages)
+	if rows := m.rows(); rows > 0 {
+		end = min(m.offset+rows, end)
+	}
+
+	width := len(fmt.Sprint(len(m.pages)))
+	for i := m.offset; i < end; i++ {
+		page := m.pages[i]
+		choice := fmt.Sprintf("%*d. %s, page %d", width, i+1, filepath.Base(page.File), page.Page)
+		if page.Rotation != 0 {
+			choice += fmt.Sprintf(" ↻ %d°", page.Rotation)
+		}
+
+		cursor := " "
+		if m.cursor == i {
+			cursor = focusedStyle.Render(">")
+			choice = focusedStyle.Render(choice)
+		}
+
+		choice = defaultStyle.Render(choice)
+
+		b.WriteString(fmt.Sprintf("%s %s\n", cursor, choice))
+	}
+
+	if m.showPreview {
+		fmt.Fprint(&b, "\n")
+		b.WriteString(m.previewView())
+	}
+
+	b.WriteString(m.footerView())
+	return b.String()
+}
+
+func PageReorderInteractive(pages []pdf.PlannedPage, logo string) (plan []pdf.PlannedPage, quit bool, err error) {
+	r := tea.NewProgram(PageReorderModel(pages, logo))
+	result, err := r.Run()
+	if err != nil {
+		return nil, false, err
+	}
+
+	model := result.(PagesModel)
+	if model.Quit {
+		return nil, true, utils.ErrCanceled
+	}
+
+	return model.GetPagePlan(), false, nil
+}
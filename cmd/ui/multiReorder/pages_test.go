@@ -0,0 +1,162 @@
+package multiReorder
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/stretchr/testify/assert"
+)
+
+func typeKeys(m PagesModel, keys ...tea.KeyMsg) PagesModel {
+	for _, key := range keys {
+		model, _ := m.Update(key)
+		m = model.(PagesModel)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestPageReorder(t *testing.T) {
+	pages := []pdf.PlannedPage{
+		{File: "/docs/a.pdf", Page: 1},
+		{File: "/docs/a.pdf", Page: 2},
+		{File: "/docs/b.pdf", Page: 1},
+	}
+
+	tests := []struct {
+		name     string
+		keys     []tea.KeyMsg
+		expected []pdf.PlannedPage
+		cursor   int
+	}{
+		{
+			name: "Move a page down",
+			keys: []tea.KeyMsg{{Type: tea.KeyDown}},
+			expected: []pdf.PlannedPage{
+				{File: "/docs/a.pdf", Page: 2},
+				{File: "/docs/a.pdf", Page: 1},
+				{File: "/docs/b.pdf", Page: 1},
+			},
+			cursor: 1,
+		},
+		{
+			name: "Delete a page",
+			keys: []tea.KeyMsg{runes("j"), runes("d")},
+			expected: []pdf.PlannedPage{
+				{File: "/docs/a.pdf", Page: 1},
+				{File: "/docs/b.pdf", Page: 1},
+			},
+			cursor: 1,
+		},
+		{
+			name: "Delete the last page",
+			keys: []tea.KeyMsg{runes("j"), runes("j"), {Type: tea.KeyDelete}},
+			expected: []pdf.PlannedPage{
+				{File: "/docs/a.pdf", Page: 1},
+				{File: "/docs/a.pdf", Page: 2},
+			},
+			cursor: 1,
+		},
+		{
+			name: "Duplicate a page",
+			keys: []tea.KeyMsg{runes("j"), runes("j"), runes("c")},
+			expected: []pdf.PlannedPage{
+				{File: "/docs/a.pdf", Page: 1},
+				{File: "/docs/a.pdf", Page: 2},
+				{File: "/docs/b.pdf", Page: 1},
+				{File: "/docs/b.pdf", Page: 1},
+			},
+			cursor: 3,
+		},
+		{
+			name: "Rotate pages",
+			keys: []tea.KeyMsg{runes("r"), runes("r"), runes("j"), runes("R")},
+			expected: []pdf.PlannedPage{
+				{File: "/docs/a.pdf", Page: 1, Rotation: 180},
+				{File: "/docs/a.pdf", Page: 2, Rotation: 270},
+				{File: "/docs/b.pdf", Page: 1},
+			},
+			cursor: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := typeKeys(PageReorderModel(pages, "merge"), tt.keys...)
+			assert.Equal(t, tt.expected, m.GetPagePlan())
+			assert.Equal(t, tt.cursor, m.cursor)
+		})
+	}
+
+	assert.Equal(t, 1, pages[0].Page, "Expected the pages passed in to be left alone")
+}
+
+func TestPageReorderEmptyPlan(t *testing.T) {
+	m := PageReorderModel([]pdf.PlannedPage{{File: "a.pdf", Page: 1}}, "merge")
+	m = typeKeys(m, runes("d"), runes("d"), runes("r"))
+	assert.Empty(t, m.GetPagePlan())
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Nil(t, cmd, "Expected enter to do nothing without pages")
+	assert.Contains(t, m.View(), "All pages were deleted")
+}
+
+func TestPageReorderView(t *testing.T) {
+	m := PageReorderModel([]pdf.PlannedPage{
+		{File: "/docs/a.pdf", Page: 3, Rotation: 90},
+		{File: "/docs/b.pdf", Page: 1},
+	}, "merge")
+
+	view := m.View()
+	assert.Contains(t, view, "1. a.pdf, page 3 ↻ 90°")
+	assert.Contains(t, view, "2. b.pdf, page 1")
+	assert.Contains(t, view, "2 pages, press enter to confirm")
+}
+
+func TestPageReorderScrolling(t *testing.T) {
+	var pages []pdf.PlannedPage
+	for page := 1; page <= 30; page++ {
+		pages = append(pages, pdf.PlannedPage{File: "long.pdf", Page: page})
+	}
+
+	model, _ := PageReorderModel(pages, "merge").Update(tea.WindowSizeMsg{Height: 25})
+	m := model.(PagesModel)
+	rows := m.rows()
+	assert.Greater(t, rows, 1)
+
+	view := m.View()
+	assert.Contains(t, view, "long.pdf, page 1\n")
+	assert.NotContains(t, view, "long.pdf, page 30")
+
+	for range pages {
+		m = typeKeys(m, runes("j"))
+	}
+	assert.Equal(t, 29, m.cursor)
+	assert.Equal(t, 30-rows, m.offset)
+
+	view = m.View()
+	assert.Contains(t, view, "long.pdf, page 30")
+	assert.NotContains(t, view, "long.pdf, page 1\n")
+}
+
+func TestPageReorderPreview(t *testing.T) {
+	t.Setenv("PDFMC_PREVIEW", "text")
+	m := PageReorderModel([]pdf.PlannedPage{{File: "/docs/a.pdf", Page: 3}, {File: "/docs/b.pdf", Page: 1}}, "merge")
+
+	model, cmd := m.Update(runes("v"))
+	m = model.(PagesModel)
+	assert.NotNil(t, cmd, "Expected the preview to be loaded")
+	assert.Contains(t, m.View(), "Preview of a.pdf, page 3")
+
+	model, cmd = m.Update(runes("j"))
+	m = model.(PagesModel)
+	assert.NotNil(t, cmd, "Expected the preview of the next page to be loaded")
+	assert.Contains(t, m.View(), "Preview of b.pdf, page 1")
+
+	_, cmd = m.Update(runes("k"))
+	assert.Nil(t, cmd, "Expected the preview to be loaded only once")
+}
@@ -0,0 +1,166 @@
+package multiSelect
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/utils"
+)
+
+// columns starts with an empty column that keeps the order of the files.
+var columns = []string{"", "name", "pages", "size", "modified", "version", "status"}
+
+const (
+	metadataBatch = 16
+	maxNameWidth  = 40
+)
+
+type fileMeta struct {
+	pdf.Metadata
+	size     int64
+	modified time.Time
+	invalid  bool
+}
+
+func (f fileMeta) status() string {
+	switch {
+	case f.invalid:
+		return "invalid"
+	case f.Locked:
+		return "locked"
+	case f.Encrypted:
+		return "encrypted"
+	}
+	return ""
+}
+
+type metadataMsg struct {
+	meta map[int]fileMeta
+	rest []int
+}
+
+// loadMetadata reads a batch at a time, so the list fills in while it's used.
+func (m Tmodel) loadMetadata(indexes []int) tea.Cmd {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	batch, rest := indexes[:min(metadataBatch, len(indexes))], indexes[min(metadataBatch, len(indexes)):]
+	pdfs, directory := m.pdfs, m.directory
+	return func() tea.Msg {
+		meta := make(map[int]fileMeta)
+		for _, i := range batch {
+			path := filepath.Join(directory, pdfs[i])
+
+			info, err := os.Stat(path)
+			if err != nil {
+				meta[i] = fileMeta{invalid: true}
+				continue
+			}
+
+			f := fileMeta{size: info.Size(), modified: info.ModTime()}
+			f.Metadata, err = pdf.ReadMetadata(path)
+			f.invalid = err != nil
+			meta[i] = f
+		}
+		return metadataMsg{meta: meta, rest: rest}
+	}
+}
+
+func (m Tmodel) sortEntries(entries []entry) {
+	if m.sortBy == "" && !m.sortDesc {
+		return
+	}
+
+	less := func(a, b entry) bool {
+		x, y := m.meta[a.index], m.meta[b.index]
+		switch m.sortBy {
+		case "name":
+			return utils.NaturalLess(a.name, b.name)
+		case "pages":
+			return x.Pages < y.Pages
+		case "size":
+			return x.size < y.size
+		case "modified":
+			return x.modified.Before(y.modified)
+		case "version":
+			return x.Version < y.Version
+		case "status":
+			return x.status() < y.status()
+		}
+		return false
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if (a.index < 0) != (b.index < 0) {
+			return a.index < 0
+		}
+		if a.index < 0 {
+			return false
+		}
+		if m.sortDesc {
+			return less(b, a)
+		}
+		return less(a, b)
+	})
+}
+
+func (m Tmodel) keepCursor(previous entry, ok bool) Tmodel {
+	if !ok {
+		return m
+	}
+	for pos, e := range m.visible() {
+		if e == previous {
+			return m.move(pos - m.cursor)
+		}
+	}
+	return m
+}
+
+func truncate(name string, width int) string {
+	runes := []rune(name)
+	if len(runes) <= width {
+		return name + strings.Repeat(" ", width-len(runes))
+	}
+	return string(runes[:width-1]) + "…"
+}
+
+func (m Tmodel) columnsView(i int) string {
+	f, ok := m.meta[i]
+	if !ok {
+		return fmt.Sprintf("%6s %9s %16s %8s %-9s", "", "", "", "", "")
+	}
+
+	pages := ""
+	if f.Pages > 0 {
+		pages = fmt.Sprint(f.Pages)
+	}
+	modified := ""
+	if !f.modified.IsZero() {
+		modified = f.modified.Format("2006-01-02 15:04")
+	}
+	return fmt.Sprintf("%6s %9s %16s %8s %-9s", pages, utils.FormatSize(f.size), modified, f.Version, f.status())
+}
+
+func (m Tmodel) columnsHeader(nameWidth int) string {
+	title := func(column, name string) string {
+		if column != m.sortBy {
+			return name
+		}
+		if m.sortDesc {
+			return name + "▼"
+		}
+		return name + "▲"
+	}
+
+	return fmt.Sprintf("%-*s %6s %9s %16s %8s %-9s", nameWidth,
+		title("name", "NAME"), title("pages", "PAGES"), title("size", "SIZE"),
+		title("modified", "MODIFIED"), title("version", "VERSION"), title("status", "STATUS"))
+}
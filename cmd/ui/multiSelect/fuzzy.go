@@ -0,0 +1,55 @@
+package multiSelect
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// fuzzyScore scores consecutive letters and letters at the start of a word
+// higher.
+func fuzzyScore(query, target string) (int, bool) {
+	q := []rune(strings.ToLower(query))
+	t := []rune(strings.ToLower(target))
+
+	score, next, last := 0, 0, -1
+	for i := 0; i < len(t) && next < len(q); i++ {
+		if t[i] != q[next] {
+			continue
+		}
+
+		switch {
+		case last >= 0 && i == last+1:
+			score += 5
+		case i == 0 || strings.ContainsRune(" _-./", t[i-1]):
+			score += 3
+		case last >= 0:
+			// letters far apart barely count
+			score -= min(i-last-1, 3)
+		}
+		last = i
+		next++
+	}
+
+	if next < len(q) {
+		return 0, false
+	}
+	return score, true
+}
+
+func fuzzyFilter(query string, names []string) []int {
+	matches := []int{}
+	scores := make(map[int]int)
+	for i, name := range names {
+		if score, ok := fuzzyScore(query, name); ok {
+			matches = append(matches, i)
+			// shorter names are closer matches
+			scores[i] = score*100 - min(utf8.RuneCountInString(name), 99)
+		}
+	}
+
+	sort.SliceStable(matches, func(a, b int) bool {
+		return scores[matches[a]] > scores[matches[b]]
+	})
+	return matches
+}
@@ -1,9 +1,14 @@
 package multiSelect
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,7 +28,6 @@ func TestMultiSelectModel(t *testing.T) {
 			expectedModel: Tmodel{
 				pdfs:      []string{"a.pdf", "b.pdf"},
 				directory: "/test",
-				selected:  make(map[int]struct{}),
 				logo:      "merge",
 			},
 		},
@@ -35,7 +39,6 @@ func TestMultiSelectModel(t *testing.T) {
 			expectedModel: Tmodel{
 				pdfs:      []string{"secret.pdf"},
 				directory: "/secure",
-				selected:  make(map[int]struct{}),
 				logo:      "encrypt",
 			},
 		},
@@ -56,28 +59,42 @@ func TestMultiSelectModel(t *testing.T) {
 func TestGetSelectedPDFs(t *testing.T) {
 	tests := []struct {
 		name     string
-		selected map[int]struct{}
+		selected []int
+		pages    map[int]string
 		pdfs     []string
 		expected []string
 	}{
 		{
 			name:     "No selections",
-			selected: map[int]struct{}{},
+			selected: nil,
 			pdfs:     []string{"a.pdf", "b.pdf"},
 			expected: nil,
 		},
 		{
 			name:     "Single selection",
-			selected: map[int]struct{}{0: {}},
+			selected: []int{0},
 			pdfs:     []string{"a.pdf", "b.pdf"},
 			expected: []string{"a.pdf"},
 		},
 		{
 			name:     "Multiple selections",
-			selected: map[int]struct{}{0: {}, 2: {}},
+			selected: []int{0, 2},
 			pdfs:     []string{"a.pdf", "b.pdf", "c.pdf"},
 			expected: []string{"a.pdf", "c.pdf"},
 		},
+		{
+			name:     "Selections in the order they were checked",
+			selected: []int{2, 0, 1},
+			pdfs:     []string{"a.pdf", "b.pdf", "c.pdf"},
+			expected: []string{"c.pdf", "a.pdf", "b.pdf"},
+		},
+		{
+			name:     "Selection with pages",
+			selected: []int{1},
+			pages:    map[int]string{1: "1-2,5"},
+			pdfs:     []string{"a.pdf", "b.pdf"},
+			expected: []string{"b.pdf[1-2,5]"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -85,6 +102,7 @@ func TestGetSelectedPDFs(t *testing.T) {
 			model := Tmodel{
 				pdfs:     tt.pdfs,
 				selected: tt.selected,
+				pages:    tt.pages,
 			}
 			assert.Equal(t, tt.expected, model.GetSelectedPDFs())
 		})
@@ -126,25 +144,36 @@ func TestUpdate(t *testing.T) {
 		{
 			name: "Select item with space",
 			initial: Tmodel{
-				pdfs:     []string{"a.pdf", "b.pdf"},
-				selected: make(map[int]struct{}),
+				pdfs: []string{"a.pdf", "b.pdf"},
 			},
 			msg: tea.KeyMsg{Type: tea.KeySpace},
 			expected: Tmodel{
 				pdfs:     []string{"a.pdf", "b.pdf"},
-				selected: map[int]struct{}{0: {}},
+				selected: []int{0},
 			},
 		},
 		{
-			name: "Deselect item with space",
+			name: "Select item after another one",
 			initial: Tmodel{
 				pdfs:     []string{"a.pdf", "b.pdf"},
-				selected: map[int]struct{}{0: {}},
+				selected: []int{1},
 			},
 			msg: tea.KeyMsg{Type: tea.KeySpace},
 			expected: Tmodel{
 				pdfs:     []string{"a.pdf", "b.pdf"},
-				selected: map[int]struct{}{},
+				selected: []int{1, 0},
+			},
+		},
+		{
+			name: "Deselect item with space",
+			initial: Tmodel{
+				pdfs:     []string{"a.pdf", "b.pdf", "c.pdf"},
+				selected: []int{2, 0, 1},
+			},
+			msg: tea.KeyMsg{Type: tea.KeySpace},
+			expected: Tmodel{
+				pdfs:     []string{"a.pdf", "b.pdf", "c.pdf"},
+				selected: []int{2, 1},
 			},
 		},
 		{
@@ -204,3 +233,315 @@ func TestUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestPageSelectionInput(t *testing.T) {
+	tests := []struct {
+		name          string
+		selection     string
+		expectedPages map[int]string
+		expectedErr   bool
+	}{
+		{
+			name:          "Valid page selection",
+			selection:     "1-2,4",
+			expectedPages: map[int]string{0: "1-2,4"},
+		},
+		{
+			name:          "Page selection out of bounds",
+			selection:     "2-5",
+			expectedPages: map[int]string{},
+			expectedErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := MultiSelectModel([]string{"a.pdf", "b.pdf"}, "/test", "merge")
+			m.meta = map[int]fileMeta{0: {Metadata: pdf.Metadata{Pages: 4}}}
+
+			model, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+			m = model.(Tmodel)
+			assert.True(t, m.editing, "Expected the page input to be open")
+
+			model, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tt.selection)})
+			model, _ = model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+			m = model.(Tmodel)
+
+			assert.Equal(t, tt.expectedPages, m.pages)
+			if tt.expectedErr {
+				assert.True(t, m.editing, "Expected the page input to stay open")
+				assert.NotEmpty(t, m.pageErr)
+			} else {
+				assert.False(t, m.editing, "Expected the page input to be closed")
+				assert.Contains(t, m.selected, 0, "Expected the PDF to be selected")
+			}
+		})
+	}
+}
+
+func TestViewOrdinals(t *testing.T) {
+	m := MultiSelectModel([]string{"a.pdf", "b.pdf", "c.pdf"}, "/test", "encrypt")
+	for _, cursor := range []int{2, 0} {
+		m.cursor = cursor
+		model, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+		m = model.(Tmodel)
+	}
+
+	view := m.View()
+	assert.Regexp(t, `\[1\]\s+c\.pdf`, view)
+	assert.Regexp(t, `\[2\]\s+a\.pdf`, view)
+	assert.Regexp(t, `\[ \]\s+b\.pdf`, view)
+}
+
+func typeKeys(m Tmodel, keys ...tea.KeyMsg) Tmodel {
+	for _, key := range keys {
+		model, _ := m.Update(key)
+		m = model.(Tmodel)
+	}
+	return m
+}
+
+func runes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestFilter(t *testing.T) {
+	pdfs := []string{"invoice-march.pdf", "report.pdf", "invoice-april.pdf", "notes.pdf"}
+	m := MultiSelectModel(pdfs, "/test", "encrypt")
+
+	m = typeKeys(m, runes("/"), runes("i"), runes("n"), runes("v"))
+	assert.True(t, m.filtering, "Expected the filter input to be open")
+	assert.Equal(t, []entry{{name: "invoice-march.pdf", index: 0}, {name: "invoice-april.pdf", index: 2}}, m.matches)
+	assert.Contains(t, m.View(), "2 of 4 PDFs match, 0 selected")
+
+	// keep the filter and select every PDF that matches
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnter}, runes("a"))
+	assert.False(t, m.filtering, "Expected the filter input to be closed")
+	assert.Equal(t, []string{"invoice-march.pdf", "invoice-april.pdf"}, m.GetSelectedPDFs())
+
+	// esc clears the filter before it quits
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Nil(t, m.matches)
+	assert.False(t, m.Quit)
+
+	m = typeKeys(m, runes("i"))
+	assert.Equal(t, []string{"report.pdf", "notes.pdf"}, m.GetSelectedPDFs())
+
+	m = typeKeys(m, runes("n"))
+	assert.Empty(t, m.GetSelectedPDFs())
+
+	m = typeKeys(m, runes("/"), runes("zzz"))
+	assert.Empty(t, m.matches)
+	assert.Contains(t, m.View(), "No PDFs match the filter")
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeySpace})
+	assert.Empty(t, m.selected, "Expected nothing to be selected without matches")
+}
+
+func TestFuzzyFilter(t *testing.T) {
+	names := []string{"summary-2024.pdf", "scan_march.pdf", "march.pdf", "mar.pdf"}
+	assert.Equal(t, []int{3, 2, 1, 0}, fuzzyFilter("mar", names))
+	// the start of a word beats letters in the middle of one
+	assert.Equal(t, []int{1, 0}, fuzzyFilter("sm", names))
+	assert.Empty(t, fuzzyFilter("xyz", names))
+}
+
+func TestViewport(t *testing.T) {
+	var pdfs []string
+	for i := range 100 {
+		pdfs = append(pdfs, fmt.Sprintf("file%03d.pdf", i))
+	}
+	m := MultiSelectModel(pdfs, "/test", "encrypt")
+
+	model, _ := m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+	m = model.(Tmodel)
+	rows := m.rows()
+	assert.Greater(t, rows, 0)
+	assert.Less(t, rows, len(pdfs))
+	assert.LessOrEqual(t, lipgloss.Height(m.View()), 30, "Expected the list to fit in the terminal")
+	assert.Contains(t, m.View(), "file000.pdf")
+	assert.NotContains(t, m.View(), "file099.pdf")
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyPgDown})
+	assert.Equal(t, rows, m.cursor)
+	assert.Equal(t, 1, m.offset)
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyEnd})
+	assert.Equal(t, 99, m.cursor)
+	assert.Contains(t, m.View(), "file099.pdf")
+	assert.NotContains(t, m.View(), "file000.pdf")
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyPgUp})
+	assert.Equal(t, 99-rows, m.cursor)
+	assert.Equal(t, 99-rows, m.offset)
+}
+
+func TestFolderNavigation(t *testing.T) {
+	tempDir := t.TempDir()
+	for _, dir := range []string{"sub/deep", ".hidden"} {
+		err := os.MkdirAll(filepath.Join(tempDir, dir), 0755)
+		assert.NoError(t, err)
+	}
+	for _, file := range []string{"a.pdf", "b.pdf", "sub/c.pdf", "sub/draft.pdf", "sub/deep/d.pdf"} {
+		err := os.WriteFile(filepath.Join(tempDir, file), nil, 0644)
+		assert.NoError(t, err)
+	}
+
+	names := func(m Tmodel) []string {
+		var names []string
+		for _, e := range m.visible() {
+			names = append(names, e.name)
+		}
+		return names
+	}
+
+	m := MultiSelectModel([]string{"a.pdf", "b.pdf"}, tempDir, "merge")
+	m.listPDFs = func(dir string) ([]string, error) {
+		// leave out the drafts like --exclude does
+		return []string{"c.pdf"}, nil
+	}
+	assert.Equal(t, []string{"sub/", "a.pdf", "b.pdf"}, names(m))
+
+	// select a.pdf and open the subfolder
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeySpace}, tea.KeyMsg{Type: tea.KeyUp}, tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, "sub", m.folder)
+	assert.Equal(t, []string{"deep/", "c.pdf"}, names(m))
+	assert.Contains(t, m.View(), filepath.Join(tempDir, "sub"))
+
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeySpace}, runes("l"))
+	assert.Equal(t, "sub", m.folder, "Expected only folders to be opened")
+
+	// go back up, the cursor is put on the folder that was left
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, "", m.folder)
+	assert.Equal(t, 0, m.cursor)
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyBackspace})
+	assert.Equal(t, "", m.folder, "Expected to stay in the directory")
+
+	assert.Equal(t, []string{"a.pdf", filepath.Join("sub", "c.pdf")}, m.GetSelectedPDFs())
+	assert.Contains(t, m.View(), "2 PDFs, 2 selected")
+}
+
+func TestMetadataColumns(t *testing.T) {
+	tempDir := t.TempDir()
+	content := `%PDF-1.4
+1 0 obj
+<< /Type /Catalog /Pages 2 0 R >>
+endobj
+2 0 obj
+<< /Type /Pages /Kids [3 0 R] /Count 1 >>
+endobj
+3 0 obj
+<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>
+endobj
+xref
+0 4
+0000000000 65535 f 
+0000000010 00000 n 
+0000000053 00000 n 
+0000000102 00000 n 
+trailer
+<< /Root 1 0 R /Size 4 >>
+startxref
+150
+%%EOF`
+	for _, file := range []string{"plain.pdf", "locked.pdf"} {
+		err := os.WriteFile(filepath.Join(tempDir, file), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+	err := os.WriteFile(filepath.Join(tempDir, "broken.pdf"), []byte("not a PDF"), 0644)
+	assert.NoError(t, err)
+	_, err = pdf.NewPDFProcessor(encrypt).InPlace().EncryptPdf("locked.pdf", tempDir, "test", "")
+	assert.NoError(t, err)
+
+	m := MultiSelectModel([]string{"plain.pdf", "locked.pdf", "broken.pdf"}, tempDir, "encrypt")
+	cmd := m.loadMetadata([]int{0, 1, 2})
+	model, next := m.Update(cmd())
+	m = model.(Tmodel)
+	assert.Nil(t, next, "Expected every PDF to be read in one batch")
+
+	assert.Equal(t, 1, m.meta[0].Pages)
+	assert.Equal(t, "locked", m.meta[1].status())
+	assert.Equal(t, "invalid", m.meta[2].status())
+
+	view := m.View()
+	assert.Regexp(t, `plain\.pdf\s+1\s+\d+ B\s+\d{4}-\d\d-\d\d \d\d:\d\d\s+1\.4`, view)
+	assert.Regexp(t, `locked\.pdf\s+[\d.]+ K?B\s+\d{4}-\d\d-\d\d \d\d:\d\d\s+1\.\d\s+locked`, view)
+	assert.Contains(t, view, "NAME")
+}
+
+func TestLoadMetadataBatches(t *testing.T) {
+	var pdfs []string
+	var indexes []int
+	for i := range metadataBatch + 4 {
+		pdfs = append(pdfs, fmt.Sprintf("file%d.pdf", i))
+		indexes = append(indexes, i)
+	}
+	m := Tmodel{pdfs: pdfs, directory: t.TempDir()}
+
+	msg := m.loadMetadata(indexes)().(metadataMsg)
+	assert.Len(t, msg.meta, metadataBatch)
+	assert.Equal(t, indexes[metadataBatch:], msg.rest)
+	assert.Nil(t, m.loadMetadata(nil))
+}
+
+func TestSortColumns(t *testing.T) {
+	m := Tmodel{
+		pdfs: []string{"b10.pdf", "b2.pdf", "a.pdf"},
+		meta: map[int]fileMeta{
+			0: {size: 300},
+			1: {size: 100},
+			2: {size: 200},
+		},
+		folders: []string{"sub"},
+	}
+	names := func(m Tmodel) []string {
+		var names []string
+		for _, e := range m.visible() {
+			names = append(names, e.name)
+		}
+		return names
+	}
+
+	// the cursor stays on b2.pdf while the order changes
+	m = typeKeys(m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	m = typeKeys(m, runes("s"))
+	assert.Equal(t, "name", m.sortBy)
+	assert.Equal(t, []string{"sub/", "a.pdf", "b2.pdf", "b10.pdf"}, names(m))
+	assert.Equal(t, 2, m.cursor)
+
+	m = typeKeys(m, runes("s"), runes("s"))
+	assert.Equal(t, "size", m.sortBy)
+	assert.Equal(t, []string{"sub/", "b2.pdf", "a.pdf", "b10.pdf"}, names(m))
+	assert.Equal(t, 1, m.cursor)
+	assert.Contains(t, m.View(), "SIZE▲")
+
+	m = typeKeys(m, runes("r"))
+	assert.Equal(t, []string{"sub/", "b10.pdf", "a.pdf", "b2.pdf"}, names(m))
+	assert.Equal(t, 3, m.cursor)
+	assert.Contains(t, m.View(), "SIZE▼")
+}
+
+func TestPreview(t *testing.T) {
+	t.Setenv("PDFMC_PREVIEW", "text")
+	tempDir := t.TempDir()
+	err := os.WriteFile(filepath.Join(tempDir, "broken.pdf"), []byte("not a PDF"), 0644)
+	assert.NoError(t, err)
+
+	m := MultiSelectModel([]string{"broken.pdf", "other.pdf"}, tempDir, "encrypt")
+	model, cmd := m.Update(runes("v"))
+	m = model.(Tmodel)
+	assert.True(t, m.showPreview)
+	assert.NotNil(t, cmd, "Expected the preview to be loaded")
+	assert.Contains(t, m.View(), "Preview of broken.pdf, page 1")
+	assert.Contains(t, m.View(), "Loading preview")
+
+	model, _ = m.Update(cmd())
+	m = model.(Tmodel)
+	assert.Contains(t, m.View(), "No preview:")
+
+	model, cmd = m.Update(runes("v"))
+	m = model.(Tmodel)
+	assert.False(t, m.showPreview)
+	assert.NotNil(t, cmd, "Expected the screen to be cleared")
+	assert.NotContains(t, m.View(), "Preview of")
+}
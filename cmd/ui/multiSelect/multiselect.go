@@ -2,10 +2,19 @@ package multiSelect
 
 import (
 	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/ui/preview"
+	"github.com/gmskazi/pdfmc/cmd/utils"
 )
 
 const (
@@ -32,11 +41,65 @@ const (
 |___/\___\__|_|  \_, | .__/\__|
                  |__/|_|       
 `
-	merge   = "merge"
-	encrypt = "encrypt"
-	decrypt = "decrypt"
+
+	logoSplit = `
+ ___      _ _ _   
+/ __|_ __| (_) |_ 
+\__ \ '_ \ | |  _|
+|___/ .__/_|_|\__|
+    |_|           
+`
+
+	logoChangePassword = `
+ ___     _              
+| _ \___| |_____ _  _ 
+|   / -_) / / -_) || |
+|_|_\___|_\_\___|\_, |
+                 |__/ 
+`
+	logoRotate = `
+ ___     _        _       
+| _ \___| |_ __ _| |_ ___ 
+|   / _ \  _/ _` + "`" + ` |  _/ -_)
+|_|_\___/\__\__,_|\__\___|
+                          
+`
+	logoOptimize = `
+  ___       _   _       _        
+ / _ \ _ __| |_(_)_ __ (_)______ 
+| (_) | '_ \  _| | '  \| |_ / -_)
+ \___/| .__/\__|_|_|_|_|_/__\___|
+      |_|                        
+`
+	logoWatermark = `
+__      __    _                          _   
+\ \    / /_ _| |_ ___ _ _ _ __  __ _ _ _| |__
+ \ \/\/ / _` + "`" + ` |  _/ -_) '_| '  \/ _` + "`" + ` | '_| / /
+  \_/\_/\__,_|\__\___|_| |_|_|_\__,_|_| |_\_\
+                                             
+`
+	merge     = "merge"
+	encrypt   = "encrypt"
+	decrypt   = "decrypt"
+	split     = "split"
+	rotate    = "rotate"
+	optimize  = "optimize"
+	watermark = "watermark"
+
+	changePassword = "change-password"
 )
 
+var headers = map[string]struct{ logo, question string }{
+	merge:          {logoMerge, "Which PDFs do you want to merge together?"},
+	encrypt:        {logoEncrypt, "Which PDFs do you want to Encrypt?"},
+	decrypt:        {logoDecrypt, "Which PDFs do you want to Decrypt?"},
+	split:          {logoSplit, "Which PDFs do you want to Split?"},
+	changePassword: {logoChangePassword, "Which PDFs do you want to change the password of?"},
+	rotate:         {logoRotate, "Which PDFs do you want to Rotate?"},
+	optimize:       {logoOptimize, "Which PDFs do you want to Optimize?"},
+	watermark:      {logoWatermark, "Which PDFs do you want to Watermark?"},
+}
+
 var (
 	defaultStyle  = lipgloss.NewStyle().PaddingLeft(1).Foreground(lipgloss.Color("#5dd2fc")).Bold(true)
 	focusedStyle  = lipgloss.NewStyle().PaddingLeft(1).Foreground(lipgloss.Color("#FCBD5F")).Bold(true)
@@ -47,87 +110,420 @@ var (
 type Tmodel struct {
 	pdfs      []string
 	directory string
-	cursor    int
-	selected  map[int]struct{}
-	logo      string
-	Quit      bool
-	autoQuit  bool
-	ErrMsg    string
+	folder    string
+	folders   []string
+	folderErr string
+	// listPDFs lists the PDFs in a subfolder, every PDF is listed when it's nil
+	listPDFs func(dir string) ([]string, error)
+	cursor   int
+	offset   int
+	height   int
+	// selected is in the order the PDFs were checked, they're processed in that order
+	selected    []int
+	meta        map[int]fileMeta
+	sortBy      string
+	sortDesc    bool
+	pages       map[int]string
+	editing     bool
+	pageInput   textinput.Model
+	pageErr     string
+	filtering   bool
+	filterInput textinput.Model
+	// matches is nil when there's no filter
+	matches     []entry
+	preview     preview.Pane
+	showPreview bool
+	logo        string
+	Quit        bool
+	autoQuit    bool
+	ErrMsg      string
 }
 
 func MultiSelectModel(pdfs []string, directory string, logo string) Tmodel {
-	return Tmodel{
-		pdfs:      pdfs,
-		directory: directory,
-		selected:  make(map[int]struct{}),
-		logo:      logo,
+	pageInput := textinput.New()
+	pageInput.Placeholder = "e.g. 1-2,5 or 3-"
+	pageInput.Cursor.Style = defaultStyle
+	pageInput.CharLimit = 64
+
+	filterInput := textinput.New()
+	filterInput.Prompt = "/"
+	filterInput.Placeholder = "type to filter"
+	filterInput.Cursor.Style = defaultStyle
+	filterInput.CharLimit = 64
+
+	m := Tmodel{
+		pdfs:        pdfs,
+		directory:   directory,
+		pages:       make(map[int]string),
+		pageInput:   pageInput,
+		filterInput: filterInput,
+		preview:     preview.New(preview.Detect()),
+		logo:        logo,
 	}
+	m, _ = m.readFolder("")
+	return m
+}
+
+type entry struct {
+	name string
+	// index is -1 for folders
+	index  int
+	folder string
 }
 
 type autoQuitMsg struct{}
 
-func (m Tmodel) Init() tea.Cmd {
-	// Set error and autoQuit if conditions aren't met
-	if m.logo == merge && len(m.pdfs) <= 1 {
-		return func() tea.Msg {
-			return autoQuitMsg{}
+// readFolder leaves out hidden folders.
+func (m Tmodel) readFolder(folder string) (Tmodel, []int) {
+	m.folder = folder
+	m.folders = nil
+	m.folderErr = ""
+
+	dir := filepath.Join(m.directory, folder)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if folder != "" {
+			m.folderErr = err.Error()
 		}
-	} else if m.logo == encrypt && len(m.pdfs) == 0 {
-		return func() tea.Msg {
-			return autoQuitMsg{}
+		return m, nil
+	}
+
+	var names []string
+	for _, e := range entries {
+		switch {
+		case strings.HasPrefix(e.Name(), "."):
+		case e.IsDir():
+			m.folders = append(m.folders, filepath.Join(folder, e.Name()))
+		case strings.HasSuffix(strings.ToLower(e.Name()), ".pdf"):
+			names = append(names, e.Name())
 		}
-	} else if m.logo == decrypt && len(m.pdfs) == 0 {
+	}
+	if folder == "" {
+		return m, nil
+	}
+
+	if m.listPDFs != nil {
+		names, err = m.listPDFs(dir)
+		if err != nil {
+			m.folderErr = err.Error()
+			return m, nil
+		}
+	}
+
+	var added []int
+	for _, name := range names {
+		path := filepath.Join(folder, name)
+		if !slices.Contains(m.pdfs, path) {
+			m.pdfs = append(m.pdfs, path)
+			added = append(added, len(m.pdfs)-1)
+		}
+	}
+	return m, added
+}
+
+func (m Tmodel) open(folder string) (Tmodel, tea.Cmd) {
+	previous := m.folder
+	m, added := m.readFolder(folder)
+	m.filtering = false
+	m.filterInput.Blur()
+	m.filterInput.SetValue("")
+	m = m.setFilter("")
+
+	for pos, e := range m.listing() {
+		if e.index < 0 && e.folder == previous {
+			m = m.move(pos)
+		}
+	}
+	return m, m.loadMetadata(added)
+}
+
+func (m Tmodel) Init() tea.Cmd {
+	// Set error and autoQuit if conditions aren't met, merge needs two PDFs
+	if len(m.folders) == 0 && (len(m.pdfs) == 0 || m.logo == merge && len(m.pdfs) <= 1) {
 		return func() tea.Msg {
 			return autoQuitMsg{}
 		}
 	}
-	return tea.ClearScreen
+
+	indexes := make([]int, len(m.pdfs))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	return tea.Batch(tea.ClearScreen, m.loadMetadata(indexes))
 }
 
 func (m Tmodel) GetSelectedPDFs() []string {
 	var selected []string
 
-	for i := range m.selected {
-		selected = append(selected, m.pdfs[i])
+	for _, i := range m.selected {
+		selected = append(selected, utils.PageSelection(m.pdfs[i], m.pages[i]))
 	}
 	return selected
 }
 
+func (m Tmodel) ordinal(i int) int {
+	return slices.Index(m.selected, i) + 1
+}
+
+func (m Tmodel) toggle(i int) Tmodel {
+	if n := m.ordinal(i); n > 0 {
+		m.selected = slices.Delete(m.selected, n-1, n)
+	} else {
+		m.selected = append(m.selected, i)
+	}
+	return m
+}
+
+func (m Tmodel) listing() []entry {
+	var entries []entry
+	for _, folder := range m.folders {
+		entries = append(entries, entry{name: filepath.Base(folder) + "/", index: -1, folder: folder})
+	}
+
+	folder := m.folder
+	if folder == "" {
+		folder = "."
+	}
+	for i, file := range m.pdfs {
+		if filepath.Dir(file) == folder {
+			entries = append(entries, entry{name: filepath.Base(file), index: i})
+		}
+	}
+	m.sortEntries(entries)
+	return entries
+}
+
+func (m Tmodel) visible() []entry {
+	if m.matches != nil {
+		return m.matches
+	}
+	return m.listing()
+}
+
+func (m Tmodel) current() (entry, bool) {
+	visible := m.visible()
+	if m.cursor < 0 || m.cursor >= len(visible) {
+		return entry{}, false
+	}
+	return visible[m.cursor], true
+}
+
+func (m Tmodel) currentPDF() (int, bool) {
+	e, ok := m.current()
+	return e.index, ok && e.index >= 0
+}
+
+func (m Tmodel) selectVisible(key string) Tmodel {
+	for _, e := range m.visible() {
+		if e.index < 0 {
+			continue
+		}
+		selected := m.ordinal(e.index) > 0
+		if key == "a" && !selected || key == "n" && selected || key == "i" {
+			m = m.toggle(e.index)
+		}
+	}
+	return m
+}
+
+func (m Tmodel) move(delta int) Tmodel {
+	m.cursor = max(min(m.cursor+delta, len(m.visible())-1), 0)
+
+	rows := m.rows()
+	if rows == 0 {
+		return m
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+	m.offset = max(min(m.offset, len(m.visible())-rows), 0)
+	return m
+}
+
+func (m Tmodel) rows() int {
+	if m.height == 0 {
+		return 0
+	}
+	height := m.height - lipgloss.Height(m.headerView()) - lipgloss.Height(m.footerView())
+	if m.showPreview {
+		height -= lipgloss.Height(m.previewView())
+	}
+	return max(height, 1)
+}
+
+func (m Tmodel) previewKey() (preview.Key, bool) {
+	i, ok := m.currentPDF()
+	if !ok {
+		return preview.Key{}, false
+	}
+
+	key := preview.Key{File: filepath.Join(m.directory, m.pdfs[i]), Page: 1}
+	if f, ok := m.meta[i]; ok && m.pages[i] != "" {
+		if ranges, err := pdf.ParsePageRanges(m.pages[i], f.Pages); err == nil {
+			key.Page = ranges[0].From
+		}
+	}
+	return key, true
+}
+
+func (m Tmodel) previewView() string {
+	key, _ := m.previewKey()
+	return m.preview.View(key)
+}
+
+func (m Tmodel) setFilter(query string) Tmodel {
+	m.matches = nil
+	if query != "" {
+		entries := m.listing()
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.name)
+		}
+
+		m.matches = []entry{}
+		for _, pos := range fuzzyFilter(query, names) {
+			m.matches = append(m.matches, entries[pos])
+		}
+	}
+	m.cursor = 0
+	m.offset = 0
+	return m
+}
+
 func (m Tmodel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	m = model.(Tmodel)
+	if !m.showPreview || m.Quit {
+		return m, cmd
+	}
+
+	key, ok := m.previewKey()
+	if !ok {
+		return m, cmd
+	}
+	return m, tea.Batch(cmd, m.preview.Load(key))
+}
+
+func (m Tmodel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case autoQuitMsg:
 		m.autoQuit = true
 		if m.logo == merge && len(m.pdfs) <= 1 {
 			m.ErrMsg = "Error: Need at least 2 PDFs to merge"
 		} else {
-			m.ErrMsg = "Error: No PDFs found to encrypt"
+			m.ErrMsg = fmt.Sprintf("Error: No PDFs found to %s", strings.ReplaceAll(m.logo, "-", " "))
 		}
 		return m, tea.Quit
+	case metadataMsg:
+		if m.meta == nil {
+			m.meta = make(map[int]fileMeta)
+		}
+		previous, ok := m.current()
+		maps.Copy(m.meta, msg.meta)
+		return m.keepCursor(previous, ok), m.loadMetadata(msg.rest)
+	case preview.Msg:
+		m.preview = m.preview.Update(msg)
+		return m, nil
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.preview = m.preview.Resize(msg.Width, msg.Height)
+		return m.move(0), nil
 	case tea.KeyMsg:
+		if m.editing {
+			return m.updatePageInput(msg)
+		}
+		if m.filtering {
+			return m.updateFilterInput(msg)
+		}
+
 		switch msg.String() {
-		case "ctrl+c", "esc":
+		case "ctrl+c":
 			m.Quit = true
 			return m, tea.Quit
 
-		case "k", "up":
-			if m.cursor > 0 {
-				m.cursor--
+		case "esc":
+			// the first esc only clears the filter
+			if m.matches != nil {
+				m.filterInput.SetValue("")
+				return m.setFilter(""), nil
 			}
+			m.Quit = true
+			return m, tea.Quit
+
+		case "k", "up":
+			m = m.move(-1)
 
 		case "j", "down":
-			if m.cursor < len(m.pdfs)-1 {
-				m.cursor++
-			}
+			m = m.move(1)
+
+		case "pgup":
+			m = m.move(-max(m.rows(), 1))
+
+		case "pgdown":
+			m = m.move(max(m.rows(), 1))
+
+		case "home":
+			m = m.move(-m.cursor)
+
+		case "end":
+			m = m.move(len(m.visible()))
 
 		case "x", " ":
-			_, ok := m.selected[m.cursor]
-			if ok {
-				delete(m.selected, m.cursor)
+			if i, ok := m.currentPDF(); ok {
+				m = m.toggle(i)
+			}
+
+		case "l", "right":
+			if e, ok := m.current(); ok && e.index < 0 {
+				return m.open(e.folder)
+			}
+
+		case "h", "left", "backspace":
+			if m.folder != "" {
+				return m.open(parentFolder(m.folder))
+			}
+
+		case "a", "n", "i":
+			m = m.selectVisible(msg.String())
+
+		case "s", "r":
+			previous, ok := m.current()
+			if msg.String() == "s" {
+				m.sortBy = columns[(slices.Index(columns, m.sortBy)+1)%len(columns)]
 			} else {
-				m.selected[m.cursor] = struct{}{}
+				m.sortDesc = !m.sortDesc
+			}
+			m = m.keepCursor(previous, ok)
+
+		case "v":
+			m.showPreview = !m.showPreview
+			m = m.move(0)
+			if !m.showPreview {
+				// images stay on the screen until it's cleared
+				return m, tea.ClearScreen
+			}
+
+		case "/":
+			m.filtering = true
+			m.filterInput.CursorEnd()
+			return m, m.filterInput.Focus()
+
+		case "p":
+			if i, ok := m.currentPDF(); ok && m.logo == merge {
+				m.editing = true
+				m.pageErr = ""
+				m.pageInput.SetValue(m.pages[i])
+				m.pageInput.CursorEnd()
+				return m, m.pageInput.Focus()
 			}
 
 		case "enter":
+			if e, ok := m.current(); ok && e.index < 0 {
+				return m.open(e.folder)
+			}
 			return m, tea.Quit
 		}
 	}
@@ -135,80 +531,286 @@ func (m Tmodel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m Tmodel) View() string {
-	var b strings.Builder
+func parentFolder(folder string) string {
+	parent := filepath.Dir(folder)
+	if parent == "." {
+		return ""
+	}
+	return parent
+}
 
-	switch m.logo {
-	case merge:
-		b.WriteString(defaultStyle.Render(logoMerge))
-		fmt.Fprint(&b, "\n\n")
-	case encrypt:
-		b.WriteString(defaultStyle.Render(logoEncrypt))
-		fmt.Fprint(&b, "\n\n")
-	case decrypt:
-		b.WriteString(defaultStyle.Render(logoDecrypt))
-		fmt.Fprint(&b, "\n\n")
+func (m Tmodel) updateFilterInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		m.Quit = true
+		return m, tea.Quit
+
+	case "esc":
+		m.filtering = false
+		m.filterInput.Blur()
+		m.filterInput.SetValue("")
+		return m.setFilter(""), nil
+
+	case "enter":
+		m.filtering = false
+		m.filterInput.Blur()
+		return m, nil
+
+	case "up":
+		return m.move(-1), nil
+
+	case "down":
+		return m.move(1), nil
+
+	case "pgup":
+		return m.move(-max(m.rows(), 1)), nil
+
+	case "pgdown":
+		return m.move(max(m.rows(), 1)), nil
 	}
 
-	if m.ErrMsg != "" {
-		b.WriteString(errorStyle.Render(m.ErrMsg))
-		return b.String()
+	var cmd tea.Cmd
+	query := m.filterInput.Value()
+	m.filterInput, cmd = m.filterInput.Update(msg)
+	if m.filterInput.Value() != query {
+		m = m.setFilter(m.filterInput.Value())
 	}
+	return m, cmd
+}
+
+func (m Tmodel) updatePageInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	i, _ := m.currentPDF()
 
-	switch m.logo {
-	case merge:
-		b.WriteString(defaultStyle.Render("Which PDFs do you want to merge together?"))
+	switch msg.String() {
+	case "ctrl+c":
+		m.Quit = true
+		return m, tea.Quit
 
-	case encrypt:
-		b.WriteString(defaultStyle.Render("Which PDFs do you want to Encrypt?"))
+	case "esc":
+		m.editing = false
+		m.pageErr = ""
+		m.pageInput.Blur()
+		return m, nil
 
-	case decrypt:
-		b.WriteString(defaultStyle.Render("Which PDFs do you want to Decrypt?"))
+	case "enter":
+		selection := strings.ReplaceAll(m.pageInput.Value(), " ", "")
+		if selection == "" {
+			delete(m.pages, i)
+		} else {
+			if f, ok := m.meta[i]; ok && f.Pages > 0 {
+				if _, err := pdf.ParsePageRanges(selection, f.Pages); err != nil {
+					m.pageErr = err.Error()
+					return m, nil
+				}
+			}
+			m.pages[i] = selection
+			if m.ordinal(i) == 0 {
+				m.selected = append(m.selected, i)
+			}
+		}
+
+		m.editing = false
+		m.pageErr = ""
+		m.pageInput.Blur()
+		return m, nil
 	}
 
+	var cmd tea.Cmd
+	m.pageInput, cmd = m.pageInput.Update(msg)
+	return m, cmd
+}
+
+func (m Tmodel) logoView() string {
+	h, ok := headers[m.logo]
+	if !ok {
+		return ""
+	}
+	return defaultStyle.Render(h.logo) + "\n\n"
+}
+
+func (m Tmodel) headerView() string {
+	var b strings.Builder
+	b.WriteString(m.logoView())
+
+	b.WriteString(defaultStyle.Render(headers[m.logo].question))
 	fmt.Fprint(&b, "\n")
-	b.WriteString(focusedStyle.Render("Select with Space or 'x', navigate with up/down or j/k"))
+	b.WriteString(focusedStyle.Render("Select with Space or 'x' in the order to process the PDFs, navigate with up/down or j/k"))
+	fmt.Fprint(&b, "\n")
+	b.WriteString(focusedStyle.Render("Press '/' to filter, 'a' to select all, 'n' to select none and 'i' to invert the selection"))
+	fmt.Fprint(&b, "\n")
+	b.WriteString(focusedStyle.Render("Open a folder with enter or right/l, go back up with backspace or left/h"))
+	fmt.Fprint(&b, "\n")
+	b.WriteString(focusedStyle.Render("Press 's' to sort by the next column, 'r' to reverse the order and 'v' to show a preview"))
+	if m.logo == merge {
+		fmt.Fprint(&b, "\n")
+		b.WriteString(focusedStyle.Render("Press 'p' to choose which pages of a PDF to merge"))
+	}
 	fmt.Fprint(&b, "\n\n")
-	b.WriteString(selectedStyle.Render("File location: ", m.directory))
+	b.WriteString(selectedStyle.Render("File location: ", filepath.Join(m.directory, m.folder)))
+	fmt.Fprint(&b, "\n")
+
+	count := fmt.Sprintf("%d PDFs, %d selected", countPDFs(m.listing()), len(m.selected))
+	if m.matches != nil {
+		count = fmt.Sprintf("%d of %d PDFs match, %d selected", countPDFs(m.visible()), countPDFs(m.listing()), len(m.selected))
+	}
+	b.WriteString(selectedStyle.Render(count))
+	fmt.Fprint(&b, "\n")
+
+	if m.filtering || m.matches != nil {
+		b.WriteString(m.filterInput.View())
+		fmt.Fprint(&b, "\n")
+	}
+	fmt.Fprint(&b, "\n")
+
+	// the titles start where the names of the PDFs do
+	b.WriteString(strings.Repeat(" ", m.ordinalWidth()+7))
+	b.WriteString(focusedStyle.PaddingLeft(0).Render(m.columnsHeader(m.nameWidth())))
+	fmt.Fprint(&b, "\n")
+
+	return b.String()
+}
+
+func (m Tmodel) ordinalWidth() int {
+	return len(strconv.Itoa(len(m.pdfs)))
+}
+
+func (m Tmodel) nameWidth() int {
+	width := len("NAME") + 1
+	for _, e := range m.listing() {
+		width = max(width, len([]rune(e.name)))
+	}
+	return min(width, maxNameWidth)
+}
+
+func countPDFs(entries []entry) int {
+	count := 0
+	for _, e := range entries {
+		if e.index >= 0 {
+			count++
+		}
+	}
+	return count
+}
+
+func (m Tmodel) footerView() string {
+	var b strings.Builder
+
+	if m.editing {
+		i, _ := m.currentPDF()
+		fmt.Fprint(&b, "\n")
+		b.WriteString(selectedStyle.Render("Pages to merge from ", m.pdfs[i]))
+		fmt.Fprint(&b, "\n")
+		b.WriteString(m.pageInput.View())
+		fmt.Fprint(&b, "\n")
+		if m.pageErr != "" {
+			b.WriteString(errorStyle.Render(m.pageErr))
+			fmt.Fprint(&b, "\n")
+		}
+		b.WriteString(focusedStyle.Render("\nPress enter to save the pages, esc to cancel."))
+		fmt.Fprint(&b, "\n\n")
+		return b.String()
+	}
+
+	if m.filtering {
+		b.WriteString(focusedStyle.Render("\nPress enter to keep the filter, esc to clear it."))
+		fmt.Fprint(&b, "\n\n")
+		return b.String()
+	}
+
+	b.WriteString(focusedStyle.Render("\nPress enter to confirm, esc to quit."))
 	fmt.Fprint(&b, "\n\n")
+	return b.String()
+}
+
+func (m Tmodel) View() string {
+	if m.ErrMsg != "" {
+		return m.logoView() + errorStyle.Render(m.ErrMsg)
+	}
 
-	for i, choice := range m.pdfs {
-		cursor := " "
-		if m.cursor == i {
+	var b strings.Builder
+	b.WriteString(m.headerView())
+
+	visible := m.visible()
+	switch {
+	case m.folderErr != "":
+		b.WriteString(errorStyle.Render(m.folderErr))
+		fmt.Fprint(&b, "\n")
+	case len(visible) == 0 && m.matches != nil:
+		b.WriteString(errorStyle.Render("No PDFs match the filter"))
+		fmt.Fprint(&b, "\n")
+	case len(visible) == 0:
+		b.WriteString(errorStyle.Render("No PDFs in this folder"))
+		fmt.Fprint(&b, "\n")
+	}
+
+	// only the PDFs that fit in the terminal are shown
+	start, end := 0, len(visible)
+	if rows := m.rows(); rows > 0 {
+		start = min(m.offset, len(visible))
+		end = min(start+rows, len(visible))
+	}
+
+	// the ordinals of the checked PDFs are padded to keep the names aligned
+	width := m.ordinalWidth()
+	nameWidth := m.nameWidth()
+	for pos := start; pos < end; pos++ {
+		e := visible[pos]
+		i := e.index
+		choice := truncate(e.name, nameWidth)
+
+		// the blank cursor is as wide as the rendered one
+		cursor := focusedStyle.Render(" ")
+		if m.cursor == pos {
 			cursor = focusedStyle.Render(">")
 		}
 
-		checked := " "
-		if _, ok := m.selected[i]; ok {
-			checked = selectedStyle.Render("x")
+		if i < 0 {
+			b.WriteString(fmt.Sprintf("%s %s %s\n", cursor, strings.Repeat(" ", width+2), focusedStyle.Render(choice)))
+			continue
+		}
+
+		checked := strings.Repeat(" ", width)
+		if n := m.ordinal(i); n > 0 {
+			checked = selectedStyle.PaddingLeft(0).Render(fmt.Sprintf("%*d", width, n))
 			choice = selectedStyle.Render(choice)
+		} else {
+			choice = defaultStyle.Render(choice)
 		}
 
-		choice = defaultStyle.Render(choice)
+		details := m.columnsView(i)
+		if pages, ok := m.pages[i]; ok {
+			details += " pages: " + pages
+		}
+		details = focusedStyle.Render(details)
 
-		b.WriteString(fmt.Sprintf("%s [%s] %s\n", cursor, checked, choice))
+		b.WriteString(fmt.Sprintf("%s [%s] %s%s\n", cursor, checked, choice, details))
 	}
 
-	b.WriteString(focusedStyle.Render("\nPress enter to confirm, esc to quit."))
-	fmt.Fprint(&b, "\n\n")
+	if m.showPreview {
+		fmt.Fprint(&b, "\n")
+		b.WriteString(m.previewView())
+	}
 
+	b.WriteString(m.footerView())
 	return b.String()
 }
 
-func MultiSelectInteractive(pdfs []string, dir string, logo string) (selectedPdfs []string, quit bool, err error) {
-	p := tea.NewProgram(MultiSelectModel(pdfs, dir, logo))
+func MultiSelectInteractive(pdfs []string, dir string, logo string, listPDFs func(dir string) ([]string, error)) (selectedPdfs []string, quit bool, err error) {
+	model := MultiSelectModel(pdfs, dir, logo)
+	model.listPDFs = listPDFs
+	p := tea.NewProgram(model)
 	result, err := p.Run()
 	if err != nil {
 		return nil, false, err
 	}
 
-	model := result.(Tmodel)
+	model = result.(Tmodel)
 	if model.autoQuit {
-		return nil, true, fmt.Errorf("%s", model.ErrMsg)
+		return nil, true, utils.NewUsageError("%s", model.ErrMsg)
 	}
 
 	if model.Quit {
-		return nil, true, fmt.Errorf("operation canceled")
+		return nil, true, utils.ErrCanceled
 	}
 
 	selectedPdfs = model.GetSelectedPDFs()
@@ -0,0 +1,192 @@
+package preview
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"strings"
+)
+
+const (
+	// saveCursor and restoreCursor keep the cursor where the image starts,
+	// the lines below it are left to the pane
+	saveCursor    = "\x1b7"
+	restoreCursor = "\x1b8"
+
+	kittyChunk = 4096
+)
+
+func encode(img image.Image, protocol Protocol, width, height int) (string, error) {
+	cols, rows := cells(img.Bounds(), width, height)
+
+	switch protocol {
+	case Kitty:
+		data, err := encodePNG(img)
+		if err != nil {
+			return "", err
+		}
+		return saveCursor + kitty(data, cols, rows) + restoreCursor, nil
+	case ITerm:
+		data, err := encodePNG(img)
+		if err != nil {
+			return "", err
+		}
+		return saveCursor + iterm(data, cols, rows) + restoreCursor, nil
+	case Sixel:
+		return saveCursor + sixel(fit(img, cols*cellWidth, rows*cellHeight)) + restoreCursor, nil
+	}
+	return "", fmt.Errorf("unknown preview protocol %s", protocol)
+}
+
+func cells(bounds image.Rectangle, width, height int) (int, int) {
+	w, h := bounds.Dx()*cellHeight, bounds.Dy()*cellWidth
+	if w == 0 || h == 0 {
+		return width, height
+	}
+	if w*height > h*width {
+		return width, max(h*width/w, 1)
+	}
+	return max(w*height/h, 1), height
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// kitty deletes the previous preview first.
+func kitty(data []byte, cols, rows int) string {
+	var b strings.Builder
+	b.WriteString("\x1b_Ga=d,q=2\x1b\\")
+
+	payload := base64.StdEncoding.EncodeToString(data)
+	for first := true; first || payload != ""; first = false {
+		chunk := payload[:min(kittyChunk, len(payload))]
+		payload = payload[len(chunk):]
+
+		more := 0
+		if payload != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(&b, "\x1b_Ga=T,f=100,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\", cols, rows, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	return b.String()
+}
+
+func iterm(data []byte, cols, rows int) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		len(data), cols, rows, base64.StdEncoding.EncodeToString(data))
+}
+
+// sixel uses the web safe palette.
+func sixel(img image.Image) string {
+	bounds := img.Bounds()
+	paletted := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), palette.WebSafe)
+	draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), img, bounds.Min)
+
+	var b strings.Builder
+	width, height := paletted.Bounds().Dx(), paletted.Bounds().Dy()
+	fmt.Fprintf(&b, "\x1bPq\"1;1;%d;%d", width, height)
+	for i, c := range palette.WebSafe {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+
+	for top := 0; top < height; top += 6 {
+		used := make(map[uint8]bool)
+		for y := top; y < min(top+6, height); y++ {
+			for x := 0; x < width; x++ {
+				used[paletted.ColorIndexAt(x, y)] = true
+			}
+		}
+
+		first := true
+		for index := range palette.WebSafe {
+			if !used[uint8(index)] {
+				continue
+			}
+			if !first {
+				b.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&b, "#%d", index)
+
+			run, last := 0, byte(0)
+			for x := 0; x <= width; x++ {
+				var bits byte
+				if x < width {
+					for y := top; y < min(top+6, height); y++ {
+						if paletted.ColorIndexAt(x, y) == uint8(index) {
+							bits |= 1 << (y - top)
+						}
+					}
+				}
+				if x > 0 && (x == width || bits != last) {
+					writeSixels(&b, last+63, run)
+					run = 0
+				}
+				last = bits
+				run++
+			}
+		}
+		b.WriteByte('-')
+	}
+
+	b.WriteString("\x1b\\")
+	return b.String()
+}
+
+func writeSixels(b *strings.Builder, c byte, run int) {
+	if run > 3 {
+		fmt.Fprintf(b, "!%d%c", run, c)
+		return
+	}
+	b.WriteString(strings.Repeat(string(c), run))
+}
+
+func fit(img image.Image, width, height int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= width && h <= height || w == 0 || h == 0 {
+		return img
+	}
+
+	scaled := image.Rect(0, 0, width, max(h*width/w, 1))
+	if w*height < h*width {
+		scaled = image.Rect(0, 0, max(w*height/h, 1), height)
+	}
+
+	dst := image.NewRGBA(scaled)
+	for y := 0; y < scaled.Dy(); y++ {
+		for x := 0; x < scaled.Dx(); x++ {
+			dst.Set(x, y, img.At(bounds.Min.X+x*w/scaled.Dx(), bounds.Min.Y+y*h/scaled.Dy()))
+		}
+	}
+	return dst
+}
+
+func rotate(img image.Image, degrees int) image.Image {
+	turns := ((degrees/90)%4 + 4) % 4
+	for ; turns > 0; turns-- {
+		bounds := img.Bounds()
+		dst := image.NewRGBA(image.Rect(0, 0, bounds.Dy(), bounds.Dx()))
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				dst.Set(bounds.Max.Y-1-y, x-bounds.Min.X, img.At(x, y))
+			}
+		}
+		img = dst
+	}
+	return img
+}
@@ -0,0 +1,194 @@
+package preview
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+)
+
+type Protocol string
+
+const (
+	Text  Protocol = "text"
+	Kitty Protocol = "kitty"
+	ITerm Protocol = "iterm"
+	Sixel Protocol = "sixel"
+)
+
+const (
+	// cellWidth and cellHeight are the assumed size of a terminal cell in
+	// pixels, the terminal scales the image to the cells anyway
+	cellWidth  = 10
+	cellHeight = 20
+
+	defaultWidth  = 60
+	defaultHeight = 12
+)
+
+var (
+	titleStyle   = lipgloss.NewStyle().PaddingLeft(1).Foreground(lipgloss.Color("#FC895F")).Bold(true)
+	textStyle    = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("#5dd2fc"))
+	loadingStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("#FCBD5F"))
+)
+
+// Detect falls back to PDFMC_PREVIEW when the terminal can't be recognised.
+func Detect() Protocol {
+	switch protocol := Protocol(strings.ToLower(os.Getenv("PDFMC_PREVIEW"))); protocol {
+	case Text, Kitty, ITerm, Sixel:
+		return protocol
+	}
+
+	term, program := os.Getenv("TERM"), os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || strings.Contains(term, "kitty") || program == "ghostty":
+		return Kitty
+	case program == "iTerm.app" || program == "WezTerm":
+		return ITerm
+	case strings.Contains(term, "sixel") || term == "foot" || strings.HasPrefix(term, "mlterm"):
+		return Sixel
+	}
+	return Text
+}
+
+type Key struct {
+	File     string
+	Page     int
+	Rotation int
+}
+
+type Msg struct {
+	Key        Key
+	View       string
+	generation int
+}
+
+type Pane struct {
+	protocol Protocol
+	width    int
+	height   int
+	views    map[Key]string
+	// the previews that were rendered before the last resize are dropped
+	generation int
+}
+
+func New(protocol Protocol) Pane {
+	return Pane{
+		protocol: protocol,
+		width:    defaultWidth,
+		height:   defaultHeight,
+		views:    make(map[Key]string),
+	}
+}
+
+func (p Pane) Resize(width, height int) Pane {
+	p.width = max(min(width-4, 2*defaultWidth), 20)
+	p.height = max(min(height/3, 2*defaultHeight), 4)
+	p.views = make(map[Key]string)
+	p.generation++
+	return p
+}
+
+func (p Pane) Update(msg Msg) Pane {
+	if msg.generation != p.generation {
+		return p
+	}
+	p.views[msg.Key] = msg.View
+	return p
+}
+
+// Load returns nil when the preview is already rendered or being rendered.
+func (p Pane) Load(key Key) tea.Cmd {
+	if _, ok := p.views[key]; ok {
+		return nil
+	}
+	// an empty view marks the preview as being rendered
+	p.views[key] = ""
+
+	protocol, width, height, generation := p.protocol, p.width, p.height, p.generation
+	return func() tea.Msg {
+		return Msg{Key: key, View: render(key, protocol, width, height), generation: generation}
+	}
+}
+
+// View is always as high as the pane, so the list above doesn't move.
+func (p Pane) View(key Key) string {
+	title := fmt.Sprintf("Preview of %s, page %d", filepath.Base(key.File), key.Page)
+	body := p.views[key]
+	switch {
+	case key.File == "":
+		title = "Nothing to preview"
+	case body == "":
+		body = loadingStyle.Render("Loading preview…")
+	}
+
+	lines := strings.Split(body, "\n")
+	lines = append(lines, make([]string, max(p.height-len(lines), 0))...)
+	return titleStyle.Render(title) + "\n" + strings.Join(lines[:p.height], "\n") + "\n"
+}
+
+func render(key Key, protocol Protocol, width, height int) string {
+	if protocol != Text {
+		if img, err := rasterize(key.File, key.Page, width, height); err == nil && img != nil {
+			img = rotate(img, key.Rotation)
+			if view, err := encode(img, protocol, width, height); err == nil {
+				return view
+			}
+		}
+	}
+
+	text, err := pdf.PageText(key.File, key.Page)
+	if err != nil {
+		return loadingStyle.Render("No preview: " + err.Error())
+	}
+	if text == "" {
+		return loadingStyle.Render("No text on this page")
+	}
+	return textView(text, width, height)
+}
+
+// rasterize uses the largest image on the page when pdftoppm and mutool aren't installed.
+func rasterize(file string, page, width, height int) (image.Image, error) {
+	size := strconv.Itoa(max(width*cellWidth, height*cellHeight))
+	commands := [][]string{
+		{"pdftoppm", "-png", "-f", strconv.Itoa(page), "-l", strconv.Itoa(page), "-scale-to", size, "-singlefile", file},
+		{"mutool", "draw", "-q", "-F", "png", "-h", size, "-o", "-", file, strconv.Itoa(page)},
+	}
+
+	for _, command := range commands {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		// #nosec G204 -- the arguments are the page and the selected file
+		out, err := exec.Command(command[0], command[1:]...).Output()
+		if err != nil {
+			continue
+		}
+		if img, _, err := image.Decode(bytes.NewReader(out)); err == nil {
+			return img, nil
+		}
+	}
+	return pdf.PageImage(file, page)
+}
+
+func textView(text string, width, height int) string {
+	lines := strings.Split(text, "\n")
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	for i, line := range lines {
+		if runes := []rune(line); len(runes) > width {
+			line = string(runes[:width-1]) + "…"
+		}
+		lines[i] = textStyle.Render(line)
+	}
+	return strings.Join(lines, "\n")
+}
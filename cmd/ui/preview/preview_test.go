@@ -0,0 +1,143 @@
+package preview
+
+import (
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected Protocol
+	}{
+		{
+			name:     "Plain terminal",
+			env:      map[string]string{"TERM": "xterm-256color"},
+			expected: Text,
+		},
+		{
+			name:     "Kitty",
+			env:      map[string]string{"TERM": "xterm-kitty"},
+			expected: Kitty,
+		},
+		{
+			name:     "iTerm2",
+			env:      map[string]string{"TERM_PROGRAM": "iTerm.app"},
+			expected: ITerm,
+		},
+		{
+			name:     "Sixel terminal",
+			env:      map[string]string{"TERM": "foot"},
+			expected: Sixel,
+		},
+		{
+			name:     "Overridden protocol",
+			env:      map[string]string{"TERM": "xterm-kitty", "PDFMC_PREVIEW": "Text"},
+			expected: Text,
+		},
+		{
+			name:     "Unknown override is ignored",
+			env:      map[string]string{"TERM": "foot", "PDFMC_PREVIEW": "ascii"},
+			expected: Sixel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"PDFMC_PREVIEW", "TERM", "TERM_PROGRAM", "KITTY_WINDOW_ID"} {
+				t.Setenv(name, tt.env[name])
+			}
+			assert.Equal(t, tt.expected, Detect())
+		})
+	}
+}
+
+func TestCells(t *testing.T) {
+	// a portrait page is as high as the pane
+	cols, rows := cells(image.Rect(0, 0, 600, 800), 60, 12)
+	assert.Equal(t, 18, cols)
+	assert.Equal(t, 12, rows)
+
+	// a wide page is as wide as the pane
+	cols, rows = cells(image.Rect(0, 0, 2000, 100), 60, 12)
+	assert.Equal(t, 60, cols)
+	assert.Equal(t, 1, rows)
+}
+
+func testImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		img.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+	return img
+}
+
+func TestKitty(t *testing.T) {
+	data := make([]byte, 4000)
+	view := kitty(data, 10, 5)
+
+	assert.True(t, strings.HasPrefix(view, "\x1b_Ga=d,q=2\x1b\\"), "Expected the previous preview to be deleted")
+	assert.Contains(t, view, "\x1b_Ga=T,f=100,q=2,C=1,c=10,r=5,m=1;")
+	assert.Contains(t, view, "\x1b_Gm=0;")
+	assert.Equal(t, 3, strings.Count(view, "\x1b_G"), "Expected the image to be sent in two chunks")
+}
+
+func TestSixel(t *testing.T) {
+	view := sixel(testImage(8, 7))
+
+	assert.True(t, strings.HasPrefix(view, "\x1bPq\"1;1;8;7"))
+	assert.True(t, strings.HasSuffix(view, "\x1b\\"))
+	assert.Equal(t, 2, strings.Count(view, "-"), "Expected a band for every 6 rows")
+	assert.Contains(t, view, "!8", "Expected runs to be compressed")
+}
+
+func TestFitAndRotate(t *testing.T) {
+	img := fit(testImage(400, 200), 100, 100)
+	assert.Equal(t, image.Rect(0, 0, 100, 50), img.Bounds())
+
+	img = fit(testImage(40, 20), 100, 100)
+	assert.Equal(t, image.Rect(0, 0, 40, 20), img.Bounds(), "Expected small images to be left alone")
+
+	rotated := rotate(testImage(4, 2), 90)
+	assert.Equal(t, image.Rect(0, 0, 2, 4), rotated.Bounds())
+	// the red top row is on the right after turning clockwise
+	assert.Equal(t, color.RGBA{R: 255, A: 255}, rotated.At(1, 0))
+	assert.Equal(t, color.RGBA{}, rotated.At(0, 0))
+
+	assert.Equal(t, image.Rect(0, 0, 4, 2), rotate(testImage(4, 2), -180).Bounds())
+}
+
+func TestPaneView(t *testing.T) {
+	p := New(Text)
+	key := Key{File: "/docs/report.pdf", Page: 2}
+
+	view := p.View(key)
+	assert.Contains(t, view, "Preview of report.pdf, page 2")
+	assert.Contains(t, view, "Loading preview")
+	assert.Equal(t, defaultHeight+1, strings.Count(view, "\n"), "Expected the pane to keep its height")
+
+	assert.NotNil(t, p.Load(key))
+	assert.Nil(t, p.Load(key), "Expected a preview to be rendered only once")
+
+	p = p.Update(Msg{Key: key, View: textView("first line\n"+strings.Repeat("x", 100), 20, 1)})
+	view = p.View(key)
+	assert.Contains(t, view, "first line")
+	assert.NotContains(t, view, "xxx", "Expected lines below the pane to be cut off")
+	assert.Equal(t, defaultHeight+1, strings.Count(view, "\n"))
+}
+
+func TestPaneResizeDropsStalePreviews(t *testing.T) {
+	p := New(Text)
+	key := Key{File: "/docs/report.pdf", Page: 2}
+
+	stale := p.Load(key)()
+	p = p.Resize(120, 60)
+	p = p.Update(stale.(Msg))
+	assert.Contains(t, p.View(key), "Loading preview", "Expected the preview of the old size to be dropped")
+	assert.NotNil(t, p.Load(key), "Expected the preview to be rendered again in the new size")
+}
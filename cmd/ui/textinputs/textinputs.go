@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/gmskazi/pdfmc/cmd/utils"
 )
 
 var (
@@ -21,14 +22,39 @@ var (
 )
 
 type Tmodel struct {
-	focusIndex int
-	inputs     []textinput.Model
-	Quit       bool
+	focusIndex      int
+	inputs          []textinput.Model
+	oldPassword     bool
+	onlyOldPassword bool
+	file            string
+	Quit            bool
 }
 
 func TextinputModel() Tmodel {
+	return newTextinputModel("Password", "Confirm Password")
+}
+
+func ChangePasswordModel() Tmodel {
+	m := newTextinputModel("Old Password", "New Password", "Confirm New Password")
+	m.oldPassword = true
+	return m
+}
+
+func OldPasswordModel() Tmodel {
+	m := newTextinputModel("Old Password")
+	m.onlyOldPassword = true
+	return m
+}
+
+func FilePasswordModel(file string) Tmodel {
+	m := newTextinputModel("Password")
+	m.file = file
+	return m
+}
+
+func newTextinputModel(placeholders ...string) Tmodel {
 	m := Tmodel{
-		inputs: make([]textinput.Model, 2),
+		inputs: make([]textinput.Model, len(placeholders)),
 	}
 
 	var t textinput.Model
@@ -36,18 +62,12 @@ func TextinputModel() Tmodel {
 		t = textinput.New()
 		t.Cursor.Style = defaultStyle
 		t.CharLimit = 32
+		t.Placeholder = placeholders[i]
+		t.EchoMode = textinput.EchoPassword
+		t.EchoCharacter = '*'
 
-		switch i {
-		case 0:
-			t.Placeholder = "Password"
-			t.EchoMode = textinput.EchoPassword
-			t.EchoCharacter = '*'
+		if i == 0 {
 			t.Focus()
-
-		case 1:
-			t.Placeholder = "Confirm Password"
-			t.EchoMode = textinput.EchoPassword
-			t.EchoCharacter = '*'
 		}
 
 		m.inputs[i] = t
@@ -76,7 +96,7 @@ func (m Tmodel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			s := msg.String()
 
 			if s == "enter" && m.focusIndex == len(m.inputs) {
-				if m.checkPasswords(m.inputs[0].Value(), m.inputs[1].Value()) {
+				if m.passwordsMatch() {
 					return m, tea.Quit
 				}
 			}
@@ -104,7 +124,7 @@ func (m Tmodel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					continue
 				}
 
-				if m.checkPasswords(m.inputs[0].Value(), m.inputs[1].Value()) {
+				if m.passwordsMatch() {
 					m.inputs[i].Blur()
 					m.inputs[i].PromptStyle = noStyle
 					m.inputs[i].TextStyle = selectedStyle
@@ -134,7 +154,15 @@ func (m Tmodel) updateInputs(msg tea.Msg) tea.Cmd {
 
 func (m Tmodel) View() string {
 	var b strings.Builder
-	b.WriteString(defaultStyle.Render("Input the password to encrypt the PDFs."))
+	if m.oldPassword {
+		b.WriteString(defaultStyle.Render("Input the old and the new password of the PDFs."))
+	} else if m.onlyOldPassword {
+		b.WriteString(defaultStyle.Render("Input the old password of the PDFs."))
+	} else if m.file != "" {
+		b.WriteString(defaultStyle.Render("Input the password of ", m.file))
+	} else {
+		b.WriteString(defaultStyle.Render("Input the password to encrypt the PDFs."))
+	}
 	fmt.Fprint(&b, "\n\n")
 
 	for i := range m.inputs {
@@ -160,9 +188,26 @@ func (m Tmodel) View() string {
 }
 
 func (m Tmodel) GetPassword() string {
+	if m.oldPassword {
+		return m.inputs[1].Value()
+	}
 	return m.inputs[0].Value()
 }
 
+func (m Tmodel) GetOldPassword() string {
+	if !m.oldPassword {
+		return ""
+	}
+	return m.inputs[0].Value()
+}
+
+func (m Tmodel) passwordsMatch() bool {
+	if len(m.inputs) < 2 {
+		return true
+	}
+	return m.checkPasswords(m.inputs[len(m.inputs)-2].Value(), m.inputs[len(m.inputs)-1].Value())
+}
+
 func (m Tmodel) checkPasswords(password, passwordConfirmation string) bool {
 	return password == passwordConfirmation
 }
@@ -176,9 +221,54 @@ func TextinputInteractive() (password string, quit bool, err error) {
 
 	tmodel := result.(Tmodel)
 	if tmodel.Quit {
-		return "", true, fmt.Errorf("operation canceled")
+		return "", true, utils.ErrCanceled
 	}
 
 	pword := tmodel.GetPassword()
 	return pword, false, nil
 }
+
+func ChangePasswordInteractive() (oldPassword, newPassword string, quit bool, err error) {
+	p := tea.NewProgram(ChangePasswordModel())
+	result, err := p.Run()
+	if err != nil {
+		return "", "", false, err
+	}
+
+	tmodel := result.(Tmodel)
+	if tmodel.Quit {
+		return "", "", true, utils.ErrCanceled
+	}
+
+	return tmodel.GetOldPassword(), tmodel.GetPassword(), false, nil
+}
+
+func OldPasswordInteractive() (password string, quit bool, err error) {
+	p := tea.NewProgram(OldPasswordModel())
+	result, err := p.Run()
+	if err != nil {
+		return "", false, err
+	}
+
+	tmodel := result.(Tmodel)
+	if tmodel.Quit {
+		return "", true, utils.ErrCanceled
+	}
+
+	return tmodel.GetPassword(), false, nil
+}
+
+func FilePasswordInteractive(file string) (password string, quit bool, err error) {
+	p := tea.NewProgram(FilePasswordModel(file))
+	result, err := p.Run()
+	if err != nil {
+		return "", false, err
+	}
+
+	tmodel := result.(Tmodel)
+	if tmodel.Quit {
+		return "", true, utils.ErrCanceled
+	}
+
+	return tmodel.GetPassword(), false, nil
+}
@@ -0,0 +1,36 @@
+/*
+Copyright © 2025 Aito Nakajima
+*/
+package cmd
+
+import (
+	"github.com/gmskazi/pdfmc/cmd/program"
+	"github.com/spf13/cobra"
+)
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Restore the PDF files overwritten by an earlier command.",
+	Long: `This is a tool to restore the PDF files that were overwritten by a command run with the --backup flag,
+the last command is undone unless an id from --list is given.
+
+The backups are kept in ~/.local/share/pdfmc/backups for the last 20 commands and up to 30 days, this can be
+changed with environment variables:
+  PDFMC_BACKUP_DIR      Directory of the backups.
+  PDFMC_BACKUP_KEEP     Number of commands to keep the backups of, 0 keeps all of them.
+  PDFMC_BACKUP_MAX_AGE  How long to keep the backups, e.g. 168h, 0 keeps them forever.`,
+	Args: usageArgs(cobra.NoArgs),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		p := program.NewProgram(cmd, args, undo)
+		return p.ExecuteUndo()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	undoCmd.Flags().String("id", "", "Restore the files of the command with this id.")
+	undoCmd.Flags().Bool("list", false, "List the commands that can be undone.")
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUndoCommand(t *testing.T) {
+	resetFlags(t, encryptCmd)
+	resetFlags(t, undoCmd)
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+	t.Setenv("PDFMC_BACKUP_DIR", filepath.Join(tempDir, "backups"))
+
+	createTestFiles(t, tempDir, []string{"file1.pdf", "file2.pdf"})
+	original, err := os.ReadFile("file1.pdf")
+	assert.NoError(t, err)
+
+	var outputBuf bytes.Buffer
+
+	rootCmd.SetOut(&outputBuf)
+	rootCmd.SetErr(&outputBuf)
+	rootCmd.SetArgs([]string{encrypt, "file1.pdf", "file2.pdf", "-p", "test", "--in-place", "--backup"})
+
+	err = rootCmd.Execute()
+	assert.NoError(t, err, "Expected the encryption to run successfully")
+
+	rootCmd.SetArgs([]string{undo, "--list"})
+	err = rootCmd.Execute()
+	assert.NoError(t, err, "Expected the backups to be listed")
+	assert.Contains(t, outputBuf.String(), encrypt)
+
+	resetFlags(t, undoCmd)
+	rootCmd.SetArgs([]string{undo})
+	err = rootCmd.Execute()
+	assert.NoError(t, err, "Expected the undo to run successfully")
+	assert.Contains(t, outputBuf.String(), "PDF file restored successfully to: "+filepath.Join(tempDir, "file1.pdf"))
+	assert.Contains(t, outputBuf.String(), "PDF file restored successfully to: "+filepath.Join(tempDir, "file2.pdf"))
+
+	restored, err := os.ReadFile("file1.pdf")
+	assert.NoError(t, err)
+	assert.Equal(t, original, restored, "Expected the unencrypted PDF file to be restored")
+
+	resetFlags(t, undoCmd)
+	rootCmd.SetArgs([]string{undo})
+	err = rootCmd.Execute()
+	assert.EqualError(t, err, "no backups found")
+	assert.Equal(t, exitFailure, exitCodeFor(err), "Unexpected exit code.")
+}
+
+func TestUndoMerge(t *testing.T) {
+	resetFlags(t, mergeCmd)
+	resetFlags(t, undoCmd)
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err, "failed to change directory: ", tempDir)
+	backups := filepath.Join(tempDir, "backups")
+	t.Setenv("PDFMC_BACKUP_DIR", backups)
+
+	createTestFiles(t, tempDir, []string{"file1.pdf", "file2.pdf"})
+
+	var outputBuf bytes.Buffer
+
+	rootCmd.SetOut(&outputBuf)
+	rootCmd.SetErr(&outputBuf)
+	rootCmd.SetArgs([]string{merge, "file1.pdf", "file2.pdf", "-n", "merged", "-p", "test", "--optimize", "--watermark", "DRAFT", "--backup"})
+
+	err = rootCmd.Execute()
+	assert.NoError(t, err, "Expected the merge to run successfully")
+	assert.FileExists(t, "merged.pdf")
+
+	// the merged PDF is only recorded, it's never copied unencrypted
+	copies, err := filepath.Glob(filepath.Join(backups, "*", "*.pdf"))
+	assert.NoError(t, err)
+	assert.Empty(t, copies, "Expected no backups of the merged PDF file")
+
+	rootCmd.SetArgs([]string{undo})
+	err = rootCmd.Execute()
+	assert.NoError(t, err, "Expected the undo to run successfully")
+	assert.Contains(t, outputBuf.String(), "PDF file removed successfully: "+filepath.Join(tempDir, "merged.pdf"))
+	assert.NoFileExists(t, "merged.pdf", "Expected the merged PDF file to be removed")
+	assert.FileExists(t, "file1.pdf")
+}
@@ -0,0 +1,16 @@
+//go:build darwin || freebsd || netbsd
+
+package utils
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+func ChangeTime(info fs.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Ctimespec.Unix())
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,14 @@
+package utils
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+func ChangeTime(info fs.FileInfo) time.Time {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return time.Unix(stat.Ctim.Unix())
+	}
+	return info.ModTime()
+}
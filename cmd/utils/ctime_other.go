@@ -0,0 +1,13 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !windows
+
+package utils
+
+import (
+	"io/fs"
+	"time"
+)
+
+// ChangeTime falls back to the modification time on this platform.
+func ChangeTime(info fs.FileInfo) time.Time {
+	return info.ModTime()
+}
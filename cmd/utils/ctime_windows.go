@@ -0,0 +1,15 @@
+package utils
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// ChangeTime returns the creation time, Windows doesn't keep the status change time.
+func ChangeTime(info fs.FileInfo) time.Time {
+	if data, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		return time.Unix(0, data.CreationTime.Nanoseconds())
+	}
+	return info.ModTime()
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrCanceled = errors.New("operation canceled")
+
+type UsageError struct {
+	Err error
+}
+
+func (e *UsageError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *UsageError) Unwrap() error {
+	return e.Err
+}
+
+func NewUsageError(format string, a ...any) error {
+	return &UsageError{Err: fmt.Errorf(format, a...)}
+}
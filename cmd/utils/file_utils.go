@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,14 @@ type FileUtils struct {
 	Interactive bool
 	dir         string
 	args        []string
+	recursive   bool
+	include     []string
+	exclude     []string
+	// baseDirs maps the PDFs found in a directory or glob to it
+	baseDirs       map[string]string
+	pageSelections bool
+	// root is the directory the UI was opened in
+	root string
 }
 
 func NewFileUtils(args []string) *FileUtils {
@@ -55,14 +64,108 @@ func (f *FileUtils) FilterPdfFiles(entries []os.DirEntry) []string {
 	return f.pdfs
 }
 
+func (f *FileUtils) SetFilters(recursive bool, include, exclude []string) error {
+	for _, pattern := range append(include, exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return NewUsageError("invalid glob %q: %w", pattern, err)
+		}
+	}
+
+	f.recursive = recursive
+	f.include = include
+	f.exclude = exclude
+	return nil
+}
+
+// AllowPageSelections accepts "file.pdf[1-2,5]" style arguments, they're
+// rejected by default so a selection is never ignored.
+func (f *FileUtils) AllowPageSelections() {
+	f.pageSelections = true
+}
+
 func (f *FileUtils) GetPdfFilesFromDir(directory string) ([]string, error) {
-	entries, err := f.ReadDirectory(directory)
-	if err != nil {
-		return nil, err
+	var pdfFiles []string
+
+	if f.recursive {
+		err := filepath.WalkDir(directory, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if entry.IsDir() || !strings.HasSuffix(strings.ToLower(entry.Name()), ".pdf") {
+				return nil
+			}
+
+			rel, err := filepath.Rel(directory, path)
+			if err != nil {
+				return err
+			}
+			pdfFiles = append(pdfFiles, rel)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		entries, err := f.ReadDirectory(directory)
+		if err != nil {
+			return nil, err
+		}
+		pdfFiles = f.FilterPdfFiles(entries)
 	}
 
-	pdfFiles := f.FilterPdfFiles(entries)
-	return pdfFiles, nil
+	f.pdfs = f.filterGlobs(directory, pdfFiles)
+	return f.pdfs, nil
+}
+
+func (f *FileUtils) filterGlobs(directory string, pdfs []string) []string {
+	if len(f.include) == 0 && len(f.exclude) == 0 {
+		return pdfs
+	}
+
+	prefix := ""
+	if f.root != "" {
+		if rel, err := filepath.Rel(f.root, directory); err == nil && filepath.IsLocal(rel) {
+			prefix = rel
+		}
+	}
+
+	var filtered []string
+	for _, pdf := range pdfs {
+		path := filepath.Join(prefix, pdf)
+		if len(f.include) > 0 && !matchesAny(f.include, path) {
+			continue
+		}
+		if matchesAny(f.exclude, path) {
+			continue
+		}
+		filtered = append(filtered, pdf)
+	}
+	return filtered
+}
+
+func matchesAny(patterns []string, pdf string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, filepath.Base(pdf)); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, pdf); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FileUtils) SplitDir(dir, file string) (string, string) {
+	base, ok := f.baseDirs[file]
+	if !ok {
+		return dir, file
+	}
+
+	rel, err := filepath.Rel(base, file)
+	if err != nil {
+		return dir, file
+	}
+	return base, rel
 }
 
 func (f *FileUtils) AddFullPathToPdfs(dir string, pdfs []string) []string {
@@ -83,6 +186,7 @@ func (f *FileUtils) CheckProvidedArgs() ([]string, string, error) {
 		if err != nil {
 			return nil, f.dir, err
 		}
+		f.root = f.dir
 		f.pdfs, err = f.GetPdfFilesFromDir(f.dir)
 		if err != nil {
 			return nil, f.dir, err
@@ -94,6 +198,7 @@ func (f *FileUtils) CheckProvidedArgs() ([]string, string, error) {
 	if len(f.args) == 1 && f.IsDirectory(f.args[0]) {
 		f.Interactive = true
 		f.dir = f.args[0]
+		f.root = f.dir
 		f.pdfs, err = f.GetPdfFilesFromDir(f.dir)
 		if err != nil {
 			return nil, f.dir, err
@@ -102,17 +207,59 @@ func (f *FileUtils) CheckProvidedArgs() ([]string, string, error) {
 
 	}
 
+	// any other mix of files and directories is processed without the UI,
+	// the PDFs in the directories are added in the place of the directory
 	f.Interactive = false
-	for _, pdf := range f.args {
-		info, err := os.Stat(pdf)
+	f.baseDirs = make(map[string]string)
+
+	var pdfs []string
+	for _, arg := range f.args {
+		file, selection := SplitPageSelection(arg)
+		if selection != "" && !f.pageSelections {
+			return nil, "", NewUsageError("page selections like %s are only supported when merging", arg)
+		}
+		if _, err := os.Stat(file); err != nil && HasGlobMeta(file) {
+			// the pattern wasn't expanded by the shell, e.g. when it's quoted
+			matches, err := ExpandGlob(file)
+			if err != nil {
+				return nil, "", err
+			}
+			if len(matches) == 0 {
+				return nil, "", NewUsageError("no PDF files match %s", file)
+			}
+			// the matches keep their folders below the root of the pattern
+			root := globRoot(globSegments(file))
+			for _, match := range matches {
+				path := PageSelection(match, selection)
+				f.baseDirs[path] = root
+				pdfs = append(pdfs, path)
+			}
+			continue
+		}
+
+		info, err := os.Stat(file)
 		if err != nil {
 			return nil, "", err
 		}
-		if info.IsDir() {
-			return nil, "", fmt.Errorf("%s is a directory not a pdf", pdf)
+		if !info.IsDir() {
+			pdfs = append(pdfs, arg)
+			continue
+		}
+
+		dirPdfs, err := f.GetPdfFilesFromDir(file)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(dirPdfs) == 0 {
+			return nil, "", NewUsageError("no PDF files found in %s", file)
+		}
+		for _, dirPdf := range dirPdfs {
+			path := filepath.Join(file, dirPdf)
+			f.baseDirs[path] = file
+			pdfs = append(pdfs, path)
 		}
 	}
 
-	f.pdfs = f.args
+	f.pdfs = pdfs
 	return f.pdfs, f.dir, nil
 }
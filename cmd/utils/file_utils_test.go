@@ -212,6 +212,7 @@ func TestCheckProvidedArgs(t *testing.T) {
 		setup               func(tempDir string)
 		args                []string
 		expectedPdfs        []string
+		pageSelections      bool
 		expectedErr         bool
 		expectedInteractive bool
 	}{
@@ -256,6 +257,31 @@ func TestCheckProvidedArgs(t *testing.T) {
 			expectedErr:         false,
 			expectedInteractive: false,
 		},
+		{
+			name: "file with page selection provided",
+			setup: func(tempDir string) {
+				file1 := filepath.Join(tempDir, "file1.pdf")
+				err := createValidPDF(file1)
+				assert.NoError(t, err)
+			},
+			args:                []string{"file1.pdf[1]"},
+			expectedPdfs:        []string{"file1.pdf[1]"},
+			pageSelections:      true,
+			expectedErr:         false,
+			expectedInteractive: false,
+		},
+		{
+			name: "page selection provided without page selections allowed",
+			setup: func(tempDir string) {
+				file1 := filepath.Join(tempDir, "file1.pdf")
+				err := createValidPDF(file1)
+				assert.NoError(t, err)
+			},
+			args:                []string{"file1.pdf[1]"},
+			expectedPdfs:        nil,
+			expectedErr:         true,
+			expectedInteractive: false,
+		},
 		{
 			name: "invalid file provided",
 			setup: func(tempDir string) {
@@ -267,6 +293,65 @@ func TestCheckProvidedArgs(t *testing.T) {
 		},
 		{
 			name: "file and directory provided",
+			setup: func(tempDir string) {
+				file1 := filepath.Join(tempDir, "file1.pdf")
+				err := createValidPDF(file1)
+				assert.NoError(t, err)
+				err = os.Mkdir(filepath.Join(tempDir, "test"), 0755)
+				assert.NoError(t, err)
+				err = createValidPDF(filepath.Join(tempDir, "test", "file2.pdf"))
+				assert.NoError(t, err)
+			},
+			args:                []string{"file1.pdf", "test"},
+			expectedPdfs:        []string{"file1.pdf", filepath.Join("test", "file2.pdf")},
+			expectedErr:         false,
+			expectedInteractive: false,
+		},
+		{
+			name: "directories provided",
+			setup: func(tempDir string) {
+				for _, dir := range []string{"a", "b"} {
+					err := os.Mkdir(filepath.Join(tempDir, dir), 0755)
+					assert.NoError(t, err)
+					err = createValidPDF(filepath.Join(tempDir, dir, "file1.pdf"))
+					assert.NoError(t, err)
+				}
+			},
+			args:                []string{"a", "b"},
+			expectedPdfs:        []string{filepath.Join("a", "file1.pdf"), filepath.Join("b", "file1.pdf")},
+			expectedErr:         false,
+			expectedInteractive: false,
+		},
+		{
+			name: "glob provided",
+			setup: func(tempDir string) {
+				err := os.Mkdir(filepath.Join(tempDir, "reports"), 0755)
+				assert.NoError(t, err)
+				for _, file := range []string{"report10.pdf", "report2.pdf", "notes.txt"} {
+					err = createValidPDF(filepath.Join(tempDir, "reports", file))
+					assert.NoError(t, err)
+				}
+			},
+			args:                []string{"reports/*[1]"},
+			expectedPdfs:        []string{filepath.Join("reports", "report2.pdf") + "[1]", filepath.Join("reports", "report10.pdf") + "[1]"},
+			pageSelections:      true,
+			expectedErr:         false,
+			expectedInteractive: false,
+		},
+		{
+			name: "glob without matches provided",
+			setup: func(tempDir string) {
+				file1 := filepath.Join(tempDir, "file1.pdf")
+				err := createValidPDF(file1)
+				assert.NoError(t, err)
+			},
+			args:                []string{"file1.pdf", "reports/*.pdf"},
+			expectedPdfs:        nil,
+			expectedErr:         true,
+			expectedInteractive: false,
+		},
+		{
+			name: "directory without PDFs provided",
 			setup: func(tempDir string) {
 				file1 := filepath.Join(tempDir, "file1.pdf")
 				err := createValidPDF(file1)
@@ -293,6 +378,9 @@ func TestCheckProvidedArgs(t *testing.T) {
 
 			f := NewFileUtils(nil)
 			f.args = tt.args
+			if tt.pageSelections {
+				f.AllowPageSelections()
+			}
 			pdfs, _, err := f.CheckProvidedArgs()
 
 			if tt.expectedErr {
@@ -312,3 +400,131 @@ func TestCheckProvidedArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPdfFilesFromDirFilters(t *testing.T) {
+	tests := []struct {
+		name      string
+		recursive bool
+		include   []string
+		exclude   []string
+		expected  []string
+	}{
+		{
+			name:     "top level only",
+			expected: []string{"invoice-1.pdf", "report.pdf"},
+		},
+		{
+			name:      "recursive",
+			recursive: true,
+			expected:  []string{"invoice-1.pdf", "report.pdf", filepath.Join("sub", "invoice-2.pdf"), filepath.Join("sub", "deep", "invoice-3.pdf")},
+		},
+		{
+			name:      "include",
+			recursive: true,
+			include:   []string{"invoice-*.pdf"},
+			expected:  []string{"invoice-1.pdf", filepath.Join("sub", "invoice-2.pdf"), filepath.Join("sub", "deep", "invoice-3.pdf")},
+		},
+		{
+			name:      "exclude a subfolder",
+			recursive: true,
+			exclude:   []string{"sub/deep/*"},
+			expected:  []string{"invoice-1.pdf", "report.pdf", filepath.Join("sub", "invoice-2.pdf")},
+		},
+		{
+			name:      "include and exclude",
+			recursive: true,
+			include:   []string{"invoice-*"},
+			exclude:   []string{"*-2.pdf"},
+			expected:  []string{"invoice-1.pdf", filepath.Join("sub", "deep", "invoice-3.pdf")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir := t.TempDir()
+			err := os.MkdirAll(filepath.Join(tempDir, "sub", "deep"), 0755)
+			assert.NoError(t, err)
+			createTestFiles(t, tempDir, []string{"invoice-1.pdf", "report.pdf", "notes.txt", filepath.Join("sub", "invoice-2.pdf"), filepath.Join("sub", "deep", "invoice-3.pdf")})
+
+			f := NewFileUtils(nil)
+			err = f.SetFilters(tt.recursive, tt.include, tt.exclude)
+			assert.NoError(t, err)
+
+			pdfFiles, err := f.GetPdfFilesFromDir(tempDir)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tt.expected, pdfFiles)
+		})
+	}
+}
+
+func TestGetPdfFilesFromDirFiltersSubfolder(t *testing.T) {
+	tempDir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(tempDir, "drafts"), 0755)
+	assert.NoError(t, err)
+	createTestFiles(t, tempDir, []string{"report.pdf", filepath.Join("drafts", "report.pdf"), filepath.Join("drafts", "notes.pdf")})
+
+	f := NewFileUtils([]string{tempDir})
+	err = f.SetFilters(false, nil, []string{"drafts/report.pdf"})
+	assert.NoError(t, err)
+	_, _, err = f.CheckProvidedArgs()
+	assert.NoError(t, err)
+
+	// the UI lists the subfolder, the exclude glob is still relative to the
+	// directory it was opened in
+	pdfFiles, err := f.GetPdfFilesFromDir(filepath.Join(tempDir, "drafts"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"notes.pdf"}, pdfFiles)
+}
+
+func TestSetFiltersInvalidGlob(t *testing.T) {
+	f := NewFileUtils(nil)
+	err := f.SetFilters(false, []string{"[invoice"}, nil)
+	assert.ErrorContains(t, err, "invalid glob")
+}
+
+func TestSplitDir(t *testing.T) {
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err)
+	err = os.MkdirAll(filepath.Join("docs", "sub"), 0755)
+	assert.NoError(t, err)
+	createTestFiles(t, tempDir, []string{"file1.pdf", filepath.Join("docs", "sub", "file2.pdf")})
+
+	f := NewFileUtils([]string{"file1.pdf", "docs"})
+	err = f.SetFilters(true, nil, nil)
+	assert.NoError(t, err)
+	pdfs, _, err := f.CheckProvidedArgs()
+	assert.NoError(t, err)
+
+	dir, file := f.SplitDir("", pdfs[0])
+	assert.Equal(t, "", dir)
+	assert.Equal(t, "file1.pdf", file)
+
+	dir, file = f.SplitDir("", pdfs[1])
+	assert.Equal(t, "docs", dir)
+	assert.Equal(t, filepath.Join("sub", "file2.pdf"), file)
+}
+
+func TestSplitDirGlob(t *testing.T) {
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err)
+	for _, sub := range []string{"a", "b"} {
+		err = os.MkdirAll(filepath.Join("docs", sub), 0755)
+		assert.NoError(t, err)
+	}
+	createTestFiles(t, tempDir, []string{filepath.Join("docs", "a", "report.pdf"), filepath.Join("docs", "b", "report.pdf")})
+
+	f := NewFileUtils([]string{"docs/**/*.pdf"})
+	pdfs, _, err := f.CheckProvidedArgs()
+	assert.NoError(t, err)
+	assert.Len(t, pdfs, 2)
+
+	dir, file := f.SplitDir("", pdfs[0])
+	assert.Equal(t, "docs", dir)
+	assert.Equal(t, filepath.Join("a", "report.pdf"), file)
+
+	dir, file = f.SplitDir("", pdfs[1])
+	assert.Equal(t, "docs", dir)
+	assert.Equal(t, filepath.Join("b", "report.pdf"), file)
+}
@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+func HasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// ExpandGlob returns the matching PDFs in natural order, "**" matches any
+// number of folders.
+func ExpandGlob(pattern string) ([]string, error) {
+	pattern = filepath.Clean(pattern)
+	segments := globSegments(pattern)
+	for _, segment := range segments {
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return nil, NewUsageError("invalid glob %q: %w", pattern, err)
+		}
+	}
+
+	var matches []string
+	if slices.Contains(segments, "**") {
+		var err error
+		matches, err = walkGlob(segments)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		matches, err = filepath.Glob(pattern)
+		if err != nil {
+			return nil, NewUsageError("invalid glob %q: %w", pattern, err)
+		}
+	}
+
+	var pdfs []string
+	for _, match := range matches {
+		if !strings.HasSuffix(strings.ToLower(match), ".pdf") {
+			continue
+		}
+		if info, err := os.Stat(match); err != nil || info.IsDir() {
+			continue
+		}
+		pdfs = append(pdfs, match)
+	}
+
+	slices.SortFunc(pdfs, func(a, b string) int {
+		switch {
+		case NaturalLess(a, b):
+			return -1
+		case NaturalLess(b, a):
+			return 1
+		}
+		return 0
+	})
+	return pdfs, nil
+}
+
+func walkGlob(segments []string) ([]string, error) {
+	root := globRoot(segments)
+
+	var matches []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root && errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() {
+			return nil
+		}
+
+		if matchSegments(segments, strings.Split(filepath.ToSlash(path), "/")) {
+			matches = append(matches, path)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+func globSegments(pattern string) []string {
+	return strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/")
+}
+
+func globRoot(segments []string) string {
+	fixed := 0
+	for fixed < len(segments)-1 && !HasGlobMeta(segments[fixed]) {
+		fixed++
+	}
+
+	root := filepath.FromSlash(strings.Join(segments[:fixed], "/"))
+	if root == "" {
+		root = "."
+		if fixed > 0 {
+			// the pattern is an absolute path
+			root = string(filepath.Separator)
+		}
+	}
+	return root
+}
+
+func matchSegments(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return len(path) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(path); i++ {
+			if matchSegments(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(path) == 0 {
+		return false
+	}
+	ok, _ := filepath.Match(pattern[0], path[0])
+	return ok && matchSegments(pattern[1:], path[1:])
+}
+
+// NaturalLess sorts "page2.pdf" before "page10.pdf".
+func NaturalLess(a, b string) bool {
+	x, y := strings.ToLower(a), strings.ToLower(b)
+
+	for x != "" && y != "" {
+		if isDigit(x[0]) && isDigit(y[0]) {
+			var xNum, yNum string
+			xNum, x = splitNumber(x)
+			yNum, y = splitNumber(y)
+
+			xTrimmed := strings.TrimLeft(xNum, "0")
+			yTrimmed := strings.TrimLeft(yNum, "0")
+			if len(xTrimmed) != len(yTrimmed) {
+				return len(xTrimmed) < len(yTrimmed)
+			}
+			if xTrimmed != yTrimmed {
+				return xTrimmed < yTrimmed
+			}
+			continue
+		}
+
+		if x[0] != y[0] {
+			return x[0] < y[0]
+		}
+		x, y = x[1:], y[1:]
+	}
+
+	if x != y {
+		return x == ""
+	}
+	// only the case or the leading zeros differ
+	return a < b
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func splitNumber(s string) (string, string) {
+	end := 0
+	for end < len(s) && isDigit(s[end]) {
+		end++
+	}
+	return s[:end], s[end:]
+}
@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandGlob(t *testing.T) {
+	tempDir := t.TempDir()
+	err := os.Chdir(tempDir)
+	assert.NoError(t, err)
+
+	for _, dir := range []string{"reports/2024/q1", "reports/2025"} {
+		err := os.MkdirAll(dir, 0755)
+		assert.NoError(t, err)
+	}
+	for _, file := range []string{
+		"reports/summary.pdf",
+		"reports/2024/q1/page10.pdf",
+		"reports/2024/q1/page2.pdf",
+		"reports/2025/page1.PDF",
+		"reports/2025/notes.txt",
+	} {
+		err := createValidPDF(filepath.FromSlash(file))
+		assert.NoError(t, err)
+	}
+
+	tests := []struct {
+		name     string
+		pattern  string
+		expected []string
+	}{
+		{
+			name:     "wildcard",
+			pattern:  "reports/*",
+			expected: []string{"reports/summary.pdf"},
+		},
+		{
+			name:     "any folder",
+			pattern:  "reports/**/*.pdf",
+			expected: []string{"reports/2024/q1/page2.pdf", "reports/2024/q1/page10.pdf", "reports/summary.pdf"},
+		},
+		{
+			name:     "any file",
+			pattern:  "reports/2025/**",
+			expected: []string{"reports/2025/page1.PDF"},
+		},
+		{
+			name:     "any folder in the middle",
+			pattern:  "./reports/**/q1/page?.pdf",
+			expected: []string{"reports/2024/q1/page2.pdf"},
+		},
+		{
+			name:     "missing folder",
+			pattern:  "missing/**/*.pdf",
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, err := ExpandGlob(filepath.FromSlash(tt.pattern))
+			assert.NoError(t, err)
+
+			var expected []string
+			for _, file := range tt.expected {
+				expected = append(expected, filepath.FromSlash(file))
+			}
+			assert.Equal(t, expected, matches)
+		})
+	}
+
+	_, err = ExpandGlob("reports/[")
+	assert.ErrorContains(t, err, "invalid glob")
+}
+
+func TestNaturalLess(t *testing.T) {
+	names := []string{"file10.pdf", "File2.pdf", "file1.pdf", "file02.pdf", "a.pdf", "file1b.pdf"}
+	sort.Slice(names, func(i, j int) bool {
+		return NaturalLess(names[i], names[j])
+	})
+	assert.Equal(t, []string{"a.pdf", "file1.pdf", "file1b.pdf", "File2.pdf", "file02.pdf", "file10.pdf"}, names)
+}
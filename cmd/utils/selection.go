@@ -0,0 +1,18 @@
+package utils
+
+import "strings"
+
+func SplitPageSelection(name string) (string, string) {
+	start := strings.LastIndex(name, "[")
+	if start <= 0 || !strings.HasSuffix(name, "]") {
+		return name, ""
+	}
+	return name[:start], name[start+1 : len(name)-1]
+}
+
+func PageSelection(name, selection string) string {
+	if selection == "" {
+		return name
+	}
+	return name + "[" + selection + "]"
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitPageSelection(t *testing.T) {
+	tests := []struct {
+		name              string
+		arg               string
+		expectedFile      string
+		expectedSelection string
+	}{
+		{
+			name:              "file without selection",
+			arg:               "file.pdf",
+			expectedFile:      "file.pdf",
+			expectedSelection: "",
+		},
+		{
+			name:              "file with selection",
+			arg:               "dir/file.pdf[1-2,5]",
+			expectedFile:      "dir/file.pdf",
+			expectedSelection: "1-2,5",
+		},
+		{
+			name:              "unterminated selection",
+			arg:               "file.pdf[1-2",
+			expectedFile:      "file.pdf[1-2",
+			expectedSelection: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, selection := SplitPageSelection(tt.arg)
+			assert.Equal(t, tt.expectedFile, file)
+			assert.Equal(t, tt.expectedSelection, selection)
+			assert.Equal(t, tt.arg, PageSelection(file, selection))
+		})
+	}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2025 Aito Nakajima
+*/
+package cmd
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/gmskazi/pdfmc/cmd/autocomplete"
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/gmskazi/pdfmc/cmd/program"
+	"github.com/spf13/cobra"
+)
+
+var watermarkCmd = &cobra.Command{
+	Use:   "watermark [files... or folder]",
+	Short: "Stamp a text or an image on PDF files.",
+	Long: `This is a tool to stamp a text like "CONFIDENTIAL" or an image like a logo on
+the pages of PDF files, on top of the content or below it.`,
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		p := program.NewProgram(cmd, args, watermark)
+		return p.ExecuteWatermark()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watermarkCmd)
+
+	defaults := pdf.DefaultWatermark()
+
+	watermarkCmd.Flags().String("text", "", "Text to stamp on the pages.")
+	watermarkCmd.Flags().String("image", "", "Image file to stamp on the pages, e.g. a PNG or JPEG logo.")
+	watermarkCmd.Flags().String("font", defaults.Font, "Font of the text, one of the PDF core fonts like Helvetica, Times-Roman or Courier.")
+	watermarkCmd.Flags().Int("size", defaults.Size, "Font size of the text in points.")
+	watermarkCmd.Flags().String("color", defaults.Color, "Color of the text, a name like red or a code like #ff0000.")
+	watermarkCmd.Flags().Float64("opacity", defaults.Opacity, "Opacity of the watermark between 0 and 1.")
+	watermarkCmd.Flags().Float64("rotation", defaults.Rotation, "Counterclockwise rotation of the watermark between -180 and 180 degrees.")
+	watermarkCmd.Flags().String("position", defaults.Position, "Position on the page, one of: "+strings.Join(pdf.WatermarkPositions, ", "))
+	watermarkCmd.Flags().Float64("scale", defaults.Scale, "Width of the image relative to the page, between 0 and 1.")
+	watermarkCmd.Flags().Bool("underlay", false, "Put the watermark below the content of the pages instead of on top of it.")
+	watermarkCmd.Flags().String("pages", "", "Only stamp these pages, e.g. 1-3,5 or 11-")
+	watermarkCmd.Flags().StringP("name", "n", "", "Add a prefix to the beginning of the file name.")
+	watermarkCmd.Flags().Bool("recursive", false, "Search the directories for PDF files recursively.")
+	watermarkCmd.Flags().StringSlice("include", nil, "Only use the PDF files in the directories that match one of these globs.")
+	watermarkCmd.Flags().StringSlice("exclude", nil, "Skip the PDF files in the directories that match one of these globs.")
+	watermarkCmd.Flags().String("output-dir", "", "Directory to write the PDF files to, it's created when it doesn't exist.")
+	watermarkCmd.Flags().String("name-template", "", nameTemplateUsage)
+	watermarkCmd.Flags().BoolP("force", "f", false, "Overwrite files that already exist.")
+	watermarkCmd.Flags().Bool("backup", false, "Keep a copy of every file that is overwritten, restore it with the undo command.")
+	watermarkCmd.Flags().Bool("in-place", false, "Overwrite the original PDF files when there is no prefix or output directory.")
+	watermarkCmd.Flags().BoolP("keep-going", "k", false, "Keep going when a file fails and print a summary at the end.")
+	watermarkCmd.Flags().IntP("jobs", "j", runtime.NumCPU(), "Number of PDF files to process at the same time.")
+
+	watermarkCmd.ValidArgsFunction = autocomplete.GetSuggestions
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/gmskazi/pdfmc/cmd/pdf"
+	"github.com/stretchr/testify/assert"
+)
+
+// Only testing non interactive mode for now
+func TestWatermarkCommand(t *testing.T) {
+	tests := []struct {
+		name           string
+		pdfs           []string
+		flags          []string
+		fileOutputs    []string
+		expectError    bool
+		exitCode       int
+		expectedOutput string
+		encrypted      bool
+		merge          bool
+	}{
+		{
+			name:           "Watermark a PDF file in place",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{watermark, "file1.pdf", "--text", "CONFIDENTIAL", "--in-place"},
+			fileOutputs:    []string{"file1.pdf"},
+			expectedOutput: "PDF file watermarked successfully to:",
+		},
+		{
+			name:           "Watermark a page range with a styled text",
+			pdfs:           []string{"file1.pdf", "file2.pdf"},
+			flags:          []string{watermark, "merged_output.pdf", "--text", "DRAFT", "--font", "Courier", "--size", "72", "--color", "red", "--opacity", "0.3", "--rotation", "-30", "--position", "tr", "--underlay", "--pages", "2", "-n", "stamped-"},
+			fileOutputs:    []string{"stamped-merged_output.pdf"},
+			expectedOutput: "PDF file watermarked successfully to:",
+			merge:          true,
+		},
+		{
+			name:           "No text or image provided",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{watermark, "file1.pdf", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "please provide the --text or --image flag",
+		},
+		{
+			name:           "Text and image provided",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{watermark, "file1.pdf", "--text", "CONFIDENTIAL", "--image", "logo.png", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "please provide either the --text flag or the --image flag",
+		},
+		{
+			name:           "Unknown position",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{watermark, "file1.pdf", "--text", "CONFIDENTIAL", "--position", "middle", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "unknown position middle",
+		},
+		{
+			name:           "Invalid opacity",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{watermark, "file1.pdf", "--text", "CONFIDENTIAL", "--opacity", "2", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "the opacity must be between 0 and 1",
+		},
+		{
+			name:           "Page range out of bounds",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{watermark, "file1.pdf", "--text", "CONFIDENTIAL", "--pages", "2", "--in-place"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "out of bounds",
+		},
+		{
+			name:           "Missing image",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{watermark, "file1.pdf", "--image", "logo.png", "--in-place"},
+			expectError:    true,
+			exitCode:       exitIO,
+			expectedOutput: "no such file or directory",
+		},
+		{
+			name:           "Original is not overwritten without in place",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{watermark, "file1.pdf", "--text", "CONFIDENTIAL"},
+			expectError:    true,
+			exitCode:       exitUsage,
+			expectedOutput: "use --in-place to overwrite the original PDF files",
+		},
+		{
+			name:           "Encrypted PDF file",
+			pdfs:           []string{"file1.pdf"},
+			flags:          []string{watermark, "file1.pdf", "--text", "CONFIDENTIAL", "--in-place"},
+			expectError:    true,
+			exitCode:       exitInvalidPDF,
+			expectedOutput: "file1.pdf is encrypted, decrypt it first",
+			encrypted:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetFlags(t, watermarkCmd)
+			tempDir := t.TempDir()
+			err := os.Chdir(tempDir)
+			assert.NoError(t, err, "failed to change directory: ", tempDir)
+
+			createTestFiles(t, tempDir, tt.pdfs)
+			if tt.encrypted {
+				encryptTestFiles(t, tempDir, tt.pdfs, "test", "")
+			}
+			if tt.merge {
+				_, err := pdf.NewPDFProcessor(merge).MergePdfs(tt.pdfs, "merged_output")
+				assert.NoError(t, err, "failed to merge test files")
+			}
+
+			var outputBuf bytes.Buffer
+
+			rootCmd.SetOut(&outputBuf)
+			rootCmd.SetErr(&outputBuf)
+			rootCmd.SetArgs(tt.flags)
+
+			err = rootCmd.Execute()
+
+			if tt.expectError {
+				assert.ErrorContains(t, err, tt.expectedOutput, "Expected an error but command ran successfully.")
+			} else {
+				assert.NoError(t, err, "Expected command to run successfully but it failed.")
+				assert.Contains(t, outputBuf.String(), tt.expectedOutput, "Expected output to contain: %s", tt.expectedOutput)
+			}
+			assert.Equal(t, tt.exitCode, exitCodeFor(err), "Unexpected exit code.")
+
+			for _, f := range tt.fileOutputs {
+				_, err := os.Stat(f)
+				assert.NoError(t, err, "Expected file %s to be created but it was not found.", f)
+			}
+		})
+	}
+}